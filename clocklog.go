@@ -0,0 +1,117 @@
+package flake
+
+import (
+	"fmt"
+	"time"
+)
+
+// ClockAnomalyKind is the kind of a ClockAnomaly.
+type ClockAnomalyKind int
+
+const (
+	// ClockBackwards is a clock behind the last issued timestamp.
+	ClockBackwards ClockAnomalyKind = iota
+	// ClockJump is a wall clock step, forward or backward, against the
+	// monotonic clock, e.g. by NTP or an operator.
+	ClockJump
+	// ClockLongWait is a wait for the next millisecond over the limit.
+	ClockLongWait
+)
+
+func (k ClockAnomalyKind) String() string {
+	switch k {
+	case ClockBackwards:
+		return "backwards"
+	case ClockJump:
+		return "jump"
+	case ClockLongWait:
+		return "long wait"
+	}
+	return fmt.Sprintf("ClockAnomalyKind(%d)", int(k))
+}
+
+// ClockAnomaly is an event which put the id ordering at risk.
+type ClockAnomaly struct {
+	Kind   ClockAnomalyKind
+	At     time.Time
+	LastTs int64         // last issued timestamp in milliseconds
+	Ts     int64         // clock in milliseconds when detected
+	Delta  time.Duration // backwards distance, jump size or wait time
+}
+
+func (a ClockAnomaly) String() string {
+	return fmt.Sprintf("%s %s at %s (last %d, now %d)",
+		a.Kind, a.Delta, a.At.Format(time.RFC3339Nano), a.LastTs, a.Ts)
+}
+
+var (
+	monoStart = time.Now()
+	// monoNow returns the monotonic reading of now, taken with the wall
+	// clock by the same time.Now, replaced in tests.
+	monoNow = func(now time.Time) time.Duration { return now.Sub(monoStart) }
+)
+
+// clockLog detects clock anomalies into a ring.
+type clockLog struct {
+	events   *ring[ClockAnomaly]
+	jump     time.Duration
+	wait     time.Duration
+	wall     int64 // wall clock of the last check in nanoseconds
+	mono     time.Duration
+	observed bool
+}
+
+// check records backwards and jumping clocks at ts, read at now.
+func (l *clockLog) check(now time.Time, ts, lastTs int64) {
+	if ts < lastTs {
+		l.events.add(ClockAnomaly{
+			Kind:   ClockBackwards,
+			At:     now,
+			LastTs: lastTs,
+			Ts:     ts,
+			Delta:  time.Duration(lastTs-ts) * time.Millisecond,
+		})
+	}
+
+	wall, mono := now.UnixNano(), monoNow(now)
+	if l.jump > 0 && l.observed {
+		skew := time.Duration(wall-l.wall) - (mono - l.mono)
+		if skew > l.jump || skew < -l.jump {
+			l.events.add(ClockAnomaly{
+				Kind:   ClockJump,
+				At:     now,
+				LastTs: lastTs,
+				Ts:     ts,
+				Delta:  skew,
+			})
+		}
+	}
+	l.wall, l.mono, l.observed = wall, mono, true
+}
+
+// checkWait records a wait of d after lastTs exceeding the limit.
+func (l *clockLog) checkWait(lastTs int64, d time.Duration) {
+	if l.wait <= 0 || d <= l.wait {
+		return
+	}
+
+	now := timeNow()
+	l.events.add(ClockAnomaly{
+		Kind:   ClockLongWait,
+		At:     now,
+		LastTs: lastTs,
+		Ts:     now.UnixNano() / 1e6,
+		Delta:  d,
+	})
+}
+
+// ClockAnomalies returns the events kept by WithClockLog, oldest first.
+func (g *Generator) ClockAnomalies() []ClockAnomaly {
+	g.Lock()
+	defer g.Unlock()
+
+	if g.clock == nil {
+		return nil
+	}
+	return g.clock.events.list()
+}
@@ -0,0 +1,90 @@
+package flake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClockLog(t *testing.T) {
+	g, err := NewGenerator(123, 0, WithClockLog(10, time.Second, time.Hour))
+	if err != nil {
+		t.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+
+	base := time.Now()
+	wall, mono := base, time.Duration(0)
+	timeNow = func() time.Time { return wall }
+	monoNow = func(time.Time) time.Duration { return mono }
+	defer func() {
+		timeNow = time.Now
+		monoNow = func(now time.Time) time.Duration { return now.Sub(monoStart) }
+	}()
+
+	g.NextID()
+
+	// wall clock stepped back 5s while 1ms passed
+	wall, mono = base.Add(-5*time.Second), time.Millisecond
+	if _, err := g.NextIDErr(); err == nil {
+		t.Errorf("Test NextIDErr failed, expected backwards error")
+	}
+	g.NextID()
+
+	ev := g.ClockAnomalies()
+	if len(ev) != 3 {
+		t.Fatalf("Test ClockAnomalies failed, got %v", ev)
+	}
+	if ev[0].Kind != ClockBackwards || ev[1].Kind != ClockJump || ev[2].Kind != ClockBackwards {
+		t.Errorf("Test ClockAnomalies failed, got %v", ev)
+	}
+	if d := ev[1].Delta; d > -4*time.Second {
+		t.Errorf("Test ClockAnomalies failed, jump %s", d)
+	}
+
+	g.clock.checkWait(0, 2*time.Hour)
+	if ev := g.ClockAnomalies(); ev[len(ev)-1].Kind != ClockLongWait {
+		t.Errorf("Test ClockAnomalies failed, expected long wait, got %v", ev)
+	}
+}
+
+func TestClockLogSingleRead(t *testing.T) {
+	g, err := NewGenerator(123, 0, WithClockLog(10, time.Second, time.Hour))
+	if err != nil {
+		t.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+
+	reads := 0
+	timeNow = func() time.Time { reads++; return time.Now() }
+	defer func() { timeNow = time.Now }()
+
+	for i := 0; i < 100; i++ {
+		g.NextID()
+	}
+	if reads != 100 {
+		t.Errorf("Test ClockLog failed, %d clock reads for 100 ids", reads)
+	}
+	if ev := g.ClockAnomalies(); len(ev) != 0 {
+		t.Errorf("Test ClockLog failed, anomalies on a steady clock: %v", ev)
+	}
+}
+
+func TestClockLogJumpOff(t *testing.T) {
+	g, err := NewGenerator(123, 0, WithClockLog(10, 0, 0))
+	if err != nil {
+		t.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+
+	base := time.Now()
+	timeNow = func() time.Time { return base }
+	monoNow = func(time.Time) time.Duration { return time.Since(base) }
+	defer func() {
+		timeNow = time.Now
+		monoNow = func(now time.Time) time.Duration { return now.Sub(monoStart) }
+	}()
+
+	for i := 0; i < 10; i++ {
+		g.NextID()
+	}
+	if ev := g.ClockAnomalies(); len(ev) != 0 {
+		t.Errorf("Test ClockLog failed, jump check off but got %v", ev)
+	}
+}
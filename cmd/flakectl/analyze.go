@@ -0,0 +1,185 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	flake "github.com/liuchong/go-flake"
+)
+
+// analysis is the report of a corpus of ids.
+type analysis struct {
+	Total      int
+	Invalid    int
+	Duplicates []flake.FlakeID // ids seen more than once, each listed once
+	First      time.Time
+	Last       time.Time
+	Buckets    []bucket // ids per interval, only intervals with ids
+	Workers    []workerCount
+	Gaps       []gap
+
+	interval time.Duration
+}
+
+type bucket struct {
+	Start time.Time
+	Count int
+}
+
+type workerCount struct {
+	Worker int64
+	Count  int
+}
+
+// gap is a stretch without any id, between two consecutive ones.
+type gap struct {
+	From, To time.Time
+}
+
+// analyze reports issuance rates, workers, gaps and duplicates of the
+// ids in the files, or stdin without any.
+func analyze(args []string) error {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	fepoch := fs.Int64("epoch", 0, "epoch in milliseconds, 0 for the default")
+	interval := fs.Duration("interval", time.Minute, "width of the rate histogram buckets")
+	minGap := fs.Duration("gap", time.Minute, "shortest stretch without ids to report")
+	fs.Parse(args)
+
+	if *interval <= 0 || *minGap <= 0 {
+		return fmt.Errorf("interval and gap must be positive")
+	}
+
+	g, err := flake.NewGenerator(0, *fepoch)
+	if err != nil {
+		return err
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	var ids []flake.FlakeID
+	invalid := 0
+	for _, name := range files {
+		err := scanIDs(name, func(_ string, id flake.FlakeID, err error) error {
+			if err != nil {
+				invalid++
+			} else {
+				ids = append(ids, id)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	a := analyzeIDs(ids, g.Schema().EpochMs, *interval, *minGap)
+	a.Total += invalid
+	a.Invalid = invalid
+	return a.print(os.Stdout)
+}
+
+// analyzeIDs builds the analysis of ids, which it sorts.
+func analyzeIDs(ids []flake.FlakeID, epoch int64, interval, minGap time.Duration) analysis {
+	a := analysis{Total: len(ids), interval: interval}
+	if len(ids) == 0 {
+		return a
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	a.First = ids[0].Time(epoch).UTC()
+	a.Last = ids[len(ids)-1].Time(epoch).UTC()
+
+	// ids are sorted, so a bucket is complete once the next one starts,
+	// and empty intervals, e.g. before a stray outlier, cost nothing
+	workers := make(map[int64]int)
+	for i, id := range ids {
+		t := id.Time(epoch).UTC()
+		if start := t.Truncate(interval); len(a.Buckets) == 0 ||
+			!a.Buckets[len(a.Buckets)-1].Start.Equal(start) {
+			a.Buckets = append(a.Buckets, bucket{Start: start})
+		}
+		a.Buckets[len(a.Buckets)-1].Count++
+		workers[id.WorkerID()]++
+
+		if i == 0 {
+			continue
+		}
+		if prev := ids[i-1]; id == prev {
+			if i < 2 || ids[i-2] != id {
+				a.Duplicates = append(a.Duplicates, id)
+			}
+		} else if pt := prev.Time(epoch).UTC(); t.Sub(pt) >= minGap {
+			a.Gaps = append(a.Gaps, gap{From: pt, To: t})
+		}
+	}
+
+	for w, n := range workers {
+		a.Workers = append(a.Workers, workerCount{Worker: w, Count: n})
+	}
+	sort.Slice(a.Workers, func(i, j int) bool {
+		if a.Workers[i].Count != a.Workers[j].Count {
+			return a.Workers[i].Count > a.Workers[j].Count
+		}
+		return a.Workers[i].Worker < a.Workers[j].Worker
+	})
+
+	return a
+}
+
+// barWidth is the length of the longest histogram bar.
+const barWidth = 40
+
+func (a analysis) print(w io.Writer) error {
+	fmt.Fprintf(w, "ids %d, invalid %d, duplicates %d, workers %d\n",
+		a.Total, a.Invalid, len(a.Duplicates), len(a.Workers))
+	if len(a.Buckets) == 0 {
+		return nil
+	}
+	fmt.Fprintf(w, "from %s to %s\n", a.First.Format(time.RFC3339Nano),
+		a.Last.Format(time.RFC3339Nano))
+
+	max := 0
+	for _, b := range a.Buckets {
+		if b.Count > max {
+			max = b.Count
+		}
+	}
+	fmt.Fprintln(w, "\nrate:")
+	for i, b := range a.Buckets {
+		if i > 0 && b.Start.Sub(a.Buckets[i-1].Start) > a.interval {
+			fmt.Fprintln(w, "  ... no ids, see gaps")
+		}
+		fmt.Fprintf(w, "  %s %8d %s\n", b.Start.Format(time.RFC3339),
+			b.Count, strings.Repeat("#", b.Count*barWidth/max))
+	}
+
+	fmt.Fprintln(w, "\nworkers:")
+	for _, c := range a.Workers {
+		fmt.Fprintf(w, "  %4d %8d %5.1f%%\n", c.Worker, c.Count,
+			float64(c.Count)*100/float64(a.Total-a.Invalid))
+	}
+
+	if len(a.Gaps) > 0 {
+		fmt.Fprintln(w, "\ngaps:")
+		for _, g := range a.Gaps {
+			fmt.Fprintf(w, "  %s .. %s %s\n", g.From.Format(time.RFC3339Nano),
+				g.To.Format(time.RFC3339Nano), g.To.Sub(g.From))
+		}
+	}
+
+	if len(a.Duplicates) > 0 {
+		fmt.Fprintln(w, "\nduplicates:")
+		for _, id := range a.Duplicates {
+			fmt.Fprintf(w, "  %d worker %d sequence %d\n", id, id.WorkerID(), id.Sequence())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	flake "github.com/liuchong/go-flake"
+)
+
+func TestAnalyzeIDs(t *testing.T) {
+	const epoch = 1234567891011
+	minute := time.Minute.Milliseconds()
+
+	var ids []flake.FlakeID
+	for _, f := range [][3]int64{
+		{0, 1, 0}, {0, 1, 1}, {10, 2, 0}, {10, 2, 0}, {10, 2, 0},
+		{3 * minute, 1, 0}, // a gap of three minutes, minute 1 and 2 empty
+	} {
+		id, _ := flake.Compose(f[0], f[1], f[2])
+		ids = append(ids, id)
+	}
+
+	a := analyzeIDs(ids, epoch, time.Minute, time.Minute)
+	if a.Total != 6 || len(a.Duplicates) != 1 || a.Duplicates[0] != ids[2] {
+		t.Errorf("Test analyzeIDs failed, duplicates %v of %d", a.Duplicates, a.Total)
+	}
+
+	counts := []int{}
+	for _, b := range a.Buckets {
+		counts = append(counts, b.Count)
+	}
+	if len(counts) != 2 || counts[0] != 5 || counts[1] != 1 ||
+		a.Buckets[1].Start.Sub(a.Buckets[0].Start) != 3*time.Minute {
+		t.Errorf("Test analyzeIDs failed, buckets %v", counts)
+	}
+
+	if len(a.Workers) != 2 || a.Workers[0] != (workerCount{1, 3}) || a.Workers[1] != (workerCount{2, 3}) {
+		t.Errorf("Test analyzeIDs failed, workers %v", a.Workers)
+	}
+
+	if len(a.Gaps) != 1 || a.Gaps[0].To.Sub(a.Gaps[0].From) != 3*time.Minute-10*time.Millisecond {
+		t.Errorf("Test analyzeIDs failed, gaps %v", a.Gaps)
+	}
+}
+
+func TestAnalyzeIDsOutlier(t *testing.T) {
+	const epoch = 1234567891011
+
+	// a stray "1" in a log is an id from 2009, next to one of now
+	now, _ := flake.Compose(time.Now().UnixMilli()-epoch, 1, 0)
+	a := analyzeIDs([]flake.FlakeID{1, now}, epoch, time.Second, time.Minute)
+	if len(a.Buckets) != 2 || len(a.Gaps) != 1 {
+		t.Errorf("Test analyzeIDs failed, %d buckets, %d gaps", len(a.Buckets), len(a.Gaps))
+	}
+
+	var b strings.Builder
+	if err := a.print(&b); err != nil || strings.Count(b.String(), "\n") > 20 {
+		t.Errorf("Test print failed, err: %v, output:\n%s", err, b.String())
+	}
+}
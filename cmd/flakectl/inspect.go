@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	flake "github.com/liuchong/go-flake"
+)
+
+type inspected struct {
+	Input    string `json:"input"`
+	ID       string `json:"id,omitempty"` // decimal, JSON numbers lose precision
+	Time     string `json:"time,omitempty"`
+	Worker   *int64 `json:"worker,omitempty"` // nil for invalid ids
+	Sequence *int64 `json:"sequence,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// cell formats v for a table, blank if nil.
+func cell(v *int64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(*v, 10)
+}
+
+// inspect decodes one id per line of the files, or stdin without any,
+// and prints the fields of each as ndjson or a table.
+func inspect(args []string) error {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	fepoch := fs.Int64("epoch", 0, "epoch in milliseconds, 0 for the default")
+	format := fs.String("format", "ndjson", "output format, ndjson or table")
+	fs.Parse(args)
+
+	g, err := flake.NewGenerator(0, *fepoch)
+	if err != nil {
+		return err
+	}
+	epoch := g.Schema().EpochMs
+
+	var out func(inspected) error
+	var flush func() error
+	switch *format {
+	case "ndjson":
+		enc := json.NewEncoder(os.Stdout)
+		out, flush = func(r inspected) error { return enc.Encode(r) }, func() error { return nil }
+	case "table":
+		tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, "INPUT\tID\tTIME\tWORKER\tSEQUENCE\tERROR")
+		out = func(r inspected) error {
+			_, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				r.Input, r.ID, r.Time, cell(r.Worker), cell(r.Sequence), r.Error)
+			return err
+		}
+		flush = tw.Flush
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	var total, invalid int
+	for _, name := range files {
+		n, bad, err := inspectFile(name, epoch, out)
+		total, invalid = total+n, invalid+bad
+		if err != nil {
+			flush()
+			return err
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d ids invalid", invalid, total)
+	}
+	return nil
+}
+
+// inspectFile inspects the ids of the file name, "-" is stdin, and
+// returns the number of ids and of invalid ones.
+func inspectFile(name string, epoch int64, out func(inspected) error) (n, invalid int, err error) {
+	err = scanIDs(name, func(s string, id flake.FlakeID, err error) error {
+		n++
+		rec := inspected{Input: s}
+		if err != nil {
+			rec.Error = err.Error()
+			invalid++
+		} else {
+			rec.ID = strconv.FormatUint(uint64(id), 10)
+			rec.Time = id.Time(epoch).UTC().Format(time.RFC3339Nano)
+			worker, seq := id.WorkerID(), id.Sequence()
+			rec.Worker, rec.Sequence = &worker, &seq
+		}
+		return out(rec)
+	})
+	return n, invalid, err
+}
+
+// scanIDs calls fn with every non-blank line of the file name, "-" is
+// stdin, and the id parsed from it.
+func scanIDs(name string, fn func(s string, id flake.FlakeID, err error) error) error {
+	r := io.Reader(os.Stdin)
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		s := strings.TrimSpace(sc.Text())
+		if s == "" {
+			continue
+		}
+		id, err := parseID(s)
+		if err := fn(s, id, err); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// parseID parses an id in decimal, hex with 0x prefix or of 16 digits,
+// or URL base64 as of FlakeID.ToString. Decimal wins for all digits.
+func parseID(s string) (flake.FlakeID, error) {
+	if h := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X"); h != s {
+		v, err := strconv.ParseUint(h, 16, 64)
+		return flake.FlakeID(v), err
+	}
+
+	if v, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return flake.FlakeID(v), nil
+	}
+
+	if len(s) == 16 {
+		if b, err := hex.DecodeString(s); err == nil {
+			return flake.DecodeBytes[flake.FlakeID](b)
+		}
+	}
+
+	var id flake.FlakeID
+	if err := id.FromString(s); err != nil {
+		return 0, errors.New("not a decimal, hex or base64 id")
+	}
+	return id, nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	flake "github.com/liuchong/go-flake"
+)
+
+func TestParseID(t *testing.T) {
+	id, _ := flake.Compose(1<<40, 1023, 42)
+
+	for _, s := range []string{
+		strconv.FormatUint(uint64(id), 10),
+		"0x" + strconv.FormatUint(uint64(id), 16),
+		hex.EncodeToString(id.ToBytes()),
+		id.ToString(),
+	} {
+		if got, err := parseID(s); err != nil || got != id {
+			t.Errorf("Test parseID failed for %q, got %d, err: %v", s, got, err)
+		}
+	}
+
+	for _, s := range []string{"", "0xzz", "not an id", "18446744073709551616"} {
+		if _, err := parseID(s); err == nil {
+			t.Errorf("Test parseID failed, %q accepted", s)
+		}
+	}
+}
+
+func TestInspectFile(t *testing.T) {
+	id, _ := flake.Compose(1<<40, 0, 0)
+	name := filepath.Join(t.TempDir(), "ids")
+	if err := os.WriteFile(name, []byte(id.ToString()+"\nnot an id\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var recs []inspected
+	n, invalid, err := inspectFile(name, flake.DefaultEpoch, func(r inspected) error {
+		recs = append(recs, r)
+		return nil
+	})
+	if err != nil || n != 2 || invalid != 1 {
+		t.Fatalf("Test inspectFile failed, got %d ids, %d invalid, err: %v", n, invalid, err)
+	}
+
+	if r := recs[0]; r.Worker == nil || *r.Worker != 0 || r.Sequence == nil || *r.Sequence != 0 {
+		t.Errorf("Test inspectFile failed, worker and sequence 0 of %q not set", r.Input)
+	}
+	if b, _ := json.Marshal(recs[1]); strings.Contains(string(b), "worker") || strings.Contains(string(b), "sequence") {
+		t.Errorf("Test inspectFile failed, invalid id encoded as %s", b)
+	}
+}
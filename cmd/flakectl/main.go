@@ -0,0 +1,48 @@
+// Command flakectl is a toolbox for flake ids.
+//
+// Usage:
+//
+//	flakectl <command> [flags]
+//
+// The commands are:
+//
+//	analyze    report rates, workers, gaps and duplicates of ids
+//	inspect    decode ids from files or stdin
+//	simulate   simulate issuance under a synthetic load
+//	vectors    print test vectors for cross-language implementations
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+var commands = map[string]func(args []string) error{
+	"analyze":  analyze,
+	"inspect":  inspect,
+	"simulate": simulateCmd,
+	"vectors":  vectors,
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: flakectl <command> [flags]")
+	fmt.Fprintln(os.Stderr, "commands: analyze, inspect, simulate, vectors")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	if err := cmd(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "flakectl %s: %s\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
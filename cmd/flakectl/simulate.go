@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	flake "github.com/liuchong/go-flake"
+	"github.com/liuchong/go-flake/simulate"
+)
+
+// simulateCmd runs the capacity simulation for one or more sequence
+// widths and prints a report line for each.
+func simulateCmd(args []string) error {
+	fs := flag.NewFlagSet("simulate", flag.ExitOnError)
+	rate := fs.Float64("rate", 1e6, "requests per second and worker")
+	poisson := fs.Bool("poisson", false, "Poisson distributed arrivals instead of constant")
+	burstRate := fs.Float64("burst-rate", 0, "requests per second during bursts, 0 for none")
+	burstPeriod := fs.Duration("burst-period", time.Second, "time between burst starts")
+	burstWidth := fs.Duration("burst-width", 10*time.Millisecond, "length of a burst")
+	duration := fs.Duration("duration", 10*time.Second, "simulated time")
+	seqBits := fs.String("seq-bits", "13", "comma separated sequence widths to compare")
+	fs.Parse(args)
+
+	g, err := flake.NewGenerator(0, 0)
+	if err != nil {
+		return err
+	}
+	layout := simulate.LayoutOf(g.Schema())
+
+	for _, s := range strings.Split(*seqBits, ",") {
+		bits, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+		if err != nil || bits < 1 || bits > 22 {
+			return fmt.Errorf("invalid sequence bits %q", s)
+		}
+
+		// the bits are taken from or given to the timestamp
+		l := layout
+		l.TimestampBits = layout.TimestampBits + layout.SequenceBits - bits
+		l.SequenceBits = bits
+
+		arrivals := simulate.Constant(*rate)
+		if *poisson {
+			arrivals = simulate.Poisson(*rate, 1)
+		}
+		if *burstRate > 0 {
+			arrivals = simulate.Burst(arrivals, simulate.Constant(*burstRate),
+				*burstPeriod, *burstWidth)
+		}
+
+		fmt.Println(simulate.Run(l, arrivals, *duration))
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"math/rand"
+	"os"
+	"strconv"
+
+	flake "github.com/liuchong/go-flake"
+)
+
+type vector struct {
+	Timestamp int64  `json:"timestamp"`
+	Worker    int64  `json:"worker"`
+	Sequence  int64  `json:"sequence"`
+	UnixMs    int64  `json:"unix_ms"`
+	ID        string `json:"id"` // decimal, JSON numbers lose precision
+	Hex       string `json:"hex"`
+	Base64URL string `json:"base64url"`
+}
+
+// vectors prints the field edge cases and n seeded random ids as
+// components -> id -> encodings for the given epoch.
+func vectors(args []string) error {
+	fs := flag.NewFlagSet("vectors", flag.ExitOnError)
+	fepoch := fs.Int64("epoch", 0, "epoch in milliseconds, 0 for the default")
+	n := fs.Int("n", 16, "number of random vectors")
+	seed := fs.Int64("seed", 1, "seed of the random vectors")
+	fs.Parse(args)
+
+	g, err := flake.NewGenerator(0, *fepoch)
+	if err != nil {
+		return err
+	}
+	schema := g.Schema()
+
+	var fields [][3]int64
+	var maxs [3]int64
+	for i, f := range schema.Fields {
+		maxs[i] = int64(-1) ^ (int64(-1) << f.Bits)
+	}
+	for _, v := range []int64{0, 1} {
+		fields = append(fields, [3]int64{v, v, v})
+	}
+	fields = append(fields,
+		[3]int64{maxs[0], 0, 0},
+		[3]int64{0, maxs[1], 0},
+		[3]int64{0, 0, maxs[2]},
+		maxs)
+
+	r := rand.New(rand.NewSource(*seed))
+	for i := 0; i < *n; i++ {
+		fields = append(fields, [3]int64{
+			r.Int63n(maxs[0] + 1), r.Int63n(maxs[1] + 1), r.Int63n(maxs[2] + 1),
+		})
+	}
+
+	out := struct {
+		Schema  flake.Schema `json:"schema"`
+		Vectors []vector     `json:"vectors"`
+	}{Schema: schema}
+
+	for _, f := range fields {
+		id, err := flake.Compose(f[0], f[1], f[2])
+		if err != nil {
+			return err
+		}
+		out.Vectors = append(out.Vectors, vector{
+			Timestamp: f[0],
+			Worker:    f[1],
+			Sequence:  f[2],
+			UnixMs:    schema.EpochMs + f[0],
+			ID:        strconv.FormatUint(uint64(id), 10),
+			Hex:       hex.EncodeToString(id.ToBytes()),
+			Base64URL: id.ToString(),
+		})
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	src, err := generate("orders", []string{"OrderID", "UserID"})
+	if err != nil {
+		t.Fatalf("Test generate failed. Err: %s", err)
+	}
+
+	for _, want := range []string{
+		"package orders",
+		"type OrderID flake.FlakeID",
+		"func NewUserID(gen flake.IDGenerator) UserID",
+		"func (id *UserID) Scan(src interface{}) error",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("Test generate failed, missing %q", want)
+		}
+	}
+
+	if _, err := generate("orders", []string{"orderID"}); err == nil {
+		t.Errorf("Test generate failed, unexported type accepted")
+	}
+}
@@ -0,0 +1,65 @@
+package flake
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// Uint64 is the constraint for ID types the codecs work with, FlakeID
+// and any user-defined type like `type OrderID uint64` satisfy it.
+type Uint64 interface {
+	~uint64
+}
+
+// PutBytes writes id into the first 8 bytes of b in big-endian order.
+func PutBytes[T Uint64](b []byte, id T) {
+	_ = b[7] // bounds check hint to compiler
+	b[0] = byte(id >> 56)
+	b[1] = byte(id >> 48)
+	b[2] = byte(id >> 40)
+	b[3] = byte(id >> 32)
+	b[4] = byte(id >> 24)
+	b[5] = byte(id >> 16)
+	b[6] = byte(id >> 8)
+	b[7] = byte(id)
+}
+
+// EncodeBytes convert id to an 8 bytes big-endian array.
+func EncodeBytes[T Uint64](id T) []byte {
+	b := make([]byte, 8)
+	PutBytes(b, id)
+	return b
+}
+
+// DecodeBytes convert an 8 bytes big-endian array to id.
+func DecodeBytes[T Uint64](b []byte) (T, error) {
+	if len(b) != 8 {
+		return 0, fmt.Errorf("id must be 8 bytes, actual got %d", len(b))
+	}
+
+	return T(
+		(uint64(b[0]) << 56) |
+			(uint64(b[1]) << 48) |
+			(uint64(b[2]) << 40) |
+			(uint64(b[3]) << 32) |
+			(uint64(b[4]) << 24) |
+			(uint64(b[5]) << 16) |
+			(uint64(b[6]) << 8) |
+			uint64(b[7]),
+	), nil
+}
+
+// EncodeString encode id to URL-compatible base64 string.
+func EncodeString[T Uint64](id T) string {
+	return base64.URLEncoding.EncodeToString(EncodeBytes(id))
+}
+
+// DecodeString decode URL-compatible base64 string to id.
+func DecodeString[T Uint64](s string) (T, error) {
+	bs, err := base64.URLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, err
+	}
+
+	return DecodeBytes[T](bs)
+}
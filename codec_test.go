@@ -0,0 +1,45 @@
+package flake
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+type orderID uint64
+
+func TestCodecRoundTrip(t *testing.T) {
+	id := orderID(0x0123456789abcdef)
+
+	b := EncodeBytes(id)
+	if !bytes.Equal(b, []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}) {
+		t.Errorf("Test EncodeBytes failed, got %x", b)
+	}
+	if got, err := DecodeBytes[orderID](b); err != nil || got != id {
+		t.Errorf("Test DecodeBytes failed, got %d, err: %v", got, err)
+	}
+	if _, err := DecodeBytes[orderID](b[:7]); err == nil {
+		t.Errorf("Test DecodeBytes failed, short input accepted")
+	}
+
+	s := EncodeString(id)
+	if got, err := DecodeString[orderID](s); err != nil || got != id {
+		t.Errorf("Test DecodeString failed, got %d, err: %v", got, err)
+	}
+	if s != FlakeID(id).ToString() {
+		t.Errorf("Test EncodeString failed, %s differs from FlakeID.ToString", s)
+	}
+
+	data, err := EncodeJSON(id)
+	if err != nil {
+		t.Errorf("Test EncodeJSON failed. Err: %s", err)
+	}
+	if got, err := DecodeJSON[orderID](data); err != nil || got != id {
+		t.Errorf("Test DecodeJSON failed, got %d, err: %v", got, err)
+	}
+
+	var fid FlakeID
+	if err := json.Unmarshal(data, &fid); err != nil || uint64(fid) != uint64(id) {
+		t.Errorf("Test FlakeID.UnmarshalJSON failed, got %d, err: %v", fid, err)
+	}
+}
@@ -0,0 +1,86 @@
+package flake
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"sort"
+)
+
+// ErrCorrupt is returned by Decompress for damaged input.
+var ErrCorrupt = errors.New("corrupt compressed ids")
+
+const compressVersion = 1
+
+var compressMagic = [2]byte{'F', 'K'}
+
+// Compress packs ids sorted as deltas in varint form:
+//
+//	"FK" | version(1) | count(uvarint) | first(uvarint) | delta(uvarint)... | crc32(4)
+//
+// The crc32 (IEEE, big-endian) covers everything before it. Ids issued
+// close together take 1-3 bytes each instead of 8. The order of ids is
+// not kept, ids itself is not modified.
+func Compress(ids []FlakeID) []byte {
+	sorted := append([]FlakeID(nil), ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	b := make([]byte, 0, 3+binary.MaxVarintLen64+len(ids)*3+4)
+	b = append(b, compressMagic[0], compressMagic[1], compressVersion)
+	b = appendUvarint(b, uint64(len(sorted)))
+
+	var prev FlakeID
+	for _, id := range sorted {
+		b = appendUvarint(b, uint64(id-prev))
+		prev = id
+	}
+
+	sum := crc32.ChecksumIEEE(b)
+	return append(b, byte(sum>>24), byte(sum>>16), byte(sum>>8), byte(sum))
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+// Decompress unpacks the output of Compress, the ids are sorted.
+func Decompress(b []byte) ([]FlakeID, error) {
+	if len(b) < 3+1+4 || b[0] != compressMagic[0] || b[1] != compressMagic[1] {
+		return nil, ErrCorrupt
+	}
+	if b[2] != compressVersion {
+		return nil, fmt.Errorf("%w: unknown version %d", ErrCorrupt, b[2])
+	}
+
+	body, sum := b[:len(b)-4], binary.BigEndian.Uint32(b[len(b)-4:])
+	if crc32.ChecksumIEEE(body) != sum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrCorrupt)
+	}
+
+	body = body[3:]
+	n, k := binary.Uvarint(body)
+	// every id takes at least one byte
+	if k <= 0 || n > uint64(len(body)-k) {
+		return nil, ErrCorrupt
+	}
+	body = body[k:]
+
+	ids := make([]FlakeID, n)
+	var prev FlakeID
+	for i := range ids {
+		d, k := binary.Uvarint(body)
+		if k <= 0 {
+			return nil, ErrCorrupt
+		}
+		body = body[k:]
+		prev += FlakeID(d)
+		ids[i] = prev
+	}
+	if len(body) != 0 {
+		return nil, ErrCorrupt
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,42 @@
+package flake
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompress(t *testing.T) {
+	g, err := NewGenerator(123, 0)
+	if err != nil {
+		t.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+
+	ids, _ := g.GenMultiIDs(100000)
+	// out of order input is sorted
+	ids[0], ids[len(ids)-1] = ids[len(ids)-1], ids[0]
+
+	b := Compress(ids)
+	if ratio := float64(len(ids)*8) / float64(len(b)); ratio < 4 {
+		t.Errorf("Test Compress failed, ratio %.1f", ratio)
+	}
+
+	got, err := Decompress(b)
+	if err != nil || len(got) != len(ids) {
+		t.Fatalf("Test Decompress failed, got %d ids, err: %v", len(got), err)
+	}
+	ids[0], ids[len(ids)-1] = ids[len(ids)-1], ids[0]
+	for i := range ids {
+		if got[i] != ids[i] {
+			t.Fatalf("Test Decompress failed at %d, got %d, expected %d", i, got[i], ids[i])
+		}
+	}
+
+	b[len(b)/2] ^= 1
+	if _, err := Decompress(b); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Test Decompress failed, corrupt input accepted, err: %v", err)
+	}
+
+	if got, err := Decompress(Compress(nil)); err != nil || len(got) != 0 {
+		t.Errorf("Test Decompress failed for empty input, got %v, err: %v", got, err)
+	}
+}
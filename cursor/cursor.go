@@ -0,0 +1,97 @@
+// Package cursor packs a FlakeID with an offset and direction into a
+// signed opaque string for paginating by id ranges.
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	flake "github.com/liuchong/go-flake"
+)
+
+// Direction is the paging direction from the cursor id.
+type Direction byte
+
+const (
+	Forward Direction = iota
+	Backward
+)
+
+const (
+	version = 1
+	// version(1) | id(8) | direction(1) | offset(4)
+	payloadSize = 14
+	macSize     = 16 // truncated HMAC-SHA256
+)
+
+// ErrInvalid is returned for malformed or tampered cursors.
+var ErrInvalid = errors.New("invalid cursor")
+
+// Cursor is the position of a page.
+type Cursor struct {
+	ID        flake.FlakeID
+	Offset    uint32
+	Direction Direction
+}
+
+// Codec signs and verifies cursors with a secret key.
+type Codec struct {
+	key []byte
+}
+
+// NewCodec returns a Codec signing with key, which must not be empty.
+func NewCodec(key []byte) (*Codec, error) {
+	if len(key) == 0 {
+		return nil, errors.New("cursor key must not be empty")
+	}
+	return &Codec{key: append([]byte(nil), key...)}, nil
+}
+
+// Encode returns the opaque URL-safe string of c.
+func (cd *Codec) Encode(c Cursor) string {
+	b := make([]byte, payloadSize, payloadSize+macSize)
+	b[0] = version
+	flake.PutBytes(b[1:], c.ID)
+	b[9] = byte(c.Direction)
+	binary.BigEndian.PutUint32(b[10:], c.Offset)
+
+	return base64.RawURLEncoding.EncodeToString(append(b, cd.mac(b)...))
+}
+
+// Decode parses and verifies a string returned by Encode.
+func (cd *Codec) Decode(s string) (Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) != payloadSize+macSize {
+		return Cursor{}, ErrInvalid
+	}
+
+	payload, mac := b[:payloadSize], b[payloadSize:]
+	if !hmac.Equal(mac, cd.mac(payload)) {
+		return Cursor{}, ErrInvalid
+	}
+	if payload[0] != version {
+		return Cursor{}, fmt.Errorf("%w: unknown version %d", ErrInvalid, payload[0])
+	}
+
+	id, _ := flake.DecodeBytes[flake.FlakeID](payload[1:9])
+	c := Cursor{
+		ID:        id,
+		Direction: Direction(payload[9]),
+		Offset:    binary.BigEndian.Uint32(payload[10:]),
+	}
+	if c.Direction != Forward && c.Direction != Backward {
+		return Cursor{}, fmt.Errorf("%w: unknown direction %d", ErrInvalid, c.Direction)
+	}
+
+	return c, nil
+}
+
+func (cd *Codec) mac(payload []byte) []byte {
+	h := hmac.New(sha256.New, cd.key)
+	h.Write(payload)
+	return h.Sum(nil)[:macSize]
+}
@@ -0,0 +1,31 @@
+package cursor
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	cd, err := NewCodec([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Test NewCodec failed. Err: %s", err)
+	}
+
+	c := Cursor{ID: 1234567890123, Offset: 20, Direction: Backward}
+	s := cd.Encode(c)
+	got, err := cd.Decode(s)
+	if err != nil || got != c {
+		t.Errorf("Test Decode failed, got %+v, err: %v", got, err)
+	}
+
+	tampered := []byte(s)
+	tampered[3] ^= 1
+	if _, err := cd.Decode(string(tampered)); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Test Decode failed, tampered cursor accepted, err: %v", err)
+	}
+
+	other, _ := NewCodec([]byte("other"))
+	if _, err := other.Decode(s); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Test Decode failed, cursor of other key accepted, err: %v", err)
+	}
+}
@@ -1,24 +1,27 @@
 package flake
 
-import "github.com/liuchong/go-flake/util"
-
 var defaultGen *Generator
 
-func init() {
-	ip, err := util.GetIP()
-	if err != nil {
-		panic(err)
-	}
-
-	// A not strictly unique worker Id
-	workerId := util.IP4toInt(ip) % (maxWorkerID + 1)
-
-	defaultGen, err = NewGenerator(workerId, 0)
-	if err != nil {
-		panic(err)
-	}
+// SetDefault replaces the generator behind GetDefault, call it before
+// any id is issued. Builds without network probing have no default
+// generator until it is set.
+func SetDefault(g *Generator) {
+	defaultGen = g
 }
 
 func GetDefault() FlakeID {
+	if defaultGen == nil {
+		panic("flake: no default generator, call SetDefault")
+	}
 	return defaultGen.NextID()
 }
+
+// NextID returns the next id of the default generator.
+func NextID() FlakeID {
+	return GetDefault()
+}
+
+// NextString returns the next id of the default generator as string.
+func NextString() string {
+	return GetDefault().ToString()
+}
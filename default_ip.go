@@ -0,0 +1,20 @@
+//go:build !tinygo && !flake_tiny && !flake_nonet
+
+package flake
+
+import "github.com/liuchong/go-flake/util"
+
+func init() {
+	ip, err := util.GetIP()
+	if err != nil {
+		panic(err)
+	}
+
+	// A not strictly unique worker Id
+	workerId := util.IP4toInt(ip) % (maxWorkerID + 1)
+
+	defaultGen, err = NewGenerator(workerId, 0)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,27 @@
+//go:build tinygo || flake_tiny || flake_nonet
+
+package flake
+
+import "testing"
+
+func TestDefaultNoNet(t *testing.T) {
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Test GetDefault failed, expected panic without SetDefault")
+			}
+		}()
+		GetDefault()
+	}()
+
+	g, err := NewGenerator(1, 0)
+	if err != nil {
+		t.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+	SetDefault(g)
+	defer SetDefault(nil)
+
+	if id := GetDefault(); id.WorkerID() != 1 {
+		t.Errorf("Test GetDefault failed, worker %d", id.WorkerID())
+	}
+}
@@ -0,0 +1,43 @@
+//go:build !tinygo && !flake_tiny && !flake_nonet
+
+package flake
+
+import "testing"
+
+func TestDefault(t *testing.T) {
+	id0, id1 := NextID(), GetDefault()
+	if id1 <= id0 {
+		t.Errorf("Test default generator failed, %d after %d", id1, id0)
+	}
+
+	var id FlakeID
+	if err := id.FromString(NextString()); err != nil || id <= id1 {
+		t.Errorf("Test NextString failed, got %d, err: %v", id, err)
+	}
+}
+
+func TestPIDWorkerID(t *testing.T) {
+	w, err := PIDWorkerID(4)
+	if err != nil {
+		t.Fatalf("Test PIDWorkerID failed. Err: %s", err)
+	}
+	if _, err := NewGenerator(w, 0); err != nil {
+		t.Errorf("Test PIDWorkerID failed, invalid worker %d. Err: %s", w, err)
+	}
+}
+
+func TestHostnameWorkerID(t *testing.T) {
+	w, err := HostnameWorkerID(nil, nil)
+	if err != nil {
+		t.Fatalf("Test HostnameWorkerID failed. Err: %s", err)
+	}
+	if _, err := NewGenerator(w, 0); err != nil {
+		t.Errorf("Test HostnameWorkerID failed, invalid worker %d. Err: %s", w, err)
+	}
+}
+
+func TestSubnetWorkerID(t *testing.T) {
+	if _, err := SubnetWorkerID("not a cidr"); err == nil {
+		t.Errorf("Test SubnetWorkerID failed, invalid cidr accepted")
+	}
+}
@@ -0,0 +1,55 @@
+package flake
+
+import (
+	"fmt"
+	"time"
+)
+
+// Description is the effective configuration of a Generator.
+type Description struct {
+	TimestampBits uint64
+	WorkerIDBits  uint64
+	SequenceBits  uint64
+	Epoch         time.Time // fepoch as time
+	WorkerID      int64
+	Exhausts      time.Time // when the timestamp bits run out
+	MaxWait       time.Duration
+	WaitStrategy  WaitStrategy
+	BorrowAhead   time.Duration // zero unless WithBorrowAhead
+}
+
+// Describe returns the configuration of the generator, e.g. to log it
+// at startup.
+func (g *Generator) Describe() Description {
+	g.Lock()
+	defer g.Unlock()
+
+	return Description{
+		TimestampBits: timestampBits,
+		WorkerIDBits:  workerIDBits,
+		SequenceBits:  sequenceBits,
+		Epoch:         time.UnixMilli(g.fepoch).UTC(),
+		WorkerID:      g.workerID,
+		Exhausts:      time.UnixMilli(g.fepoch + maxTimestamp).UTC(),
+		MaxWait:       g.maxWait,
+		WaitStrategy:  g.wait,
+		BorrowAhead:   time.Duration(g.borrow) * time.Millisecond,
+	}
+}
+
+func (d Description) String() string {
+	maxWait := "unbounded"
+	if d.MaxWait > 0 {
+		maxWait = d.MaxWait.String()
+	}
+
+	s := fmt.Sprintf("layout timestamp(%d)|worker(%d)|sequence(%d), "+
+		"epoch %s, worker %d, exhausts %s, max wait %s, wait %s",
+		d.TimestampBits, d.WorkerIDBits, d.SequenceBits,
+		d.Epoch.Format(time.RFC3339Nano), d.WorkerID,
+		d.Exhausts.Format(time.RFC3339Nano), maxWait, d.WaitStrategy)
+	if d.BorrowAhead > 0 {
+		s += fmt.Sprintf(", borrow ahead %s", d.BorrowAhead)
+	}
+	return s
+}
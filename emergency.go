@@ -0,0 +1,61 @@
+package flake
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+)
+
+// EmergencyGenerator issues ids of the usual layout with the current
+// timestamp but random worker and sequence bits, for when neither the
+// clock nor the worker assignment can be trusted. Ids are unique only
+// with high probability (23 random bits per millisecond) and are not
+// ordered within a millisecond. Every id is passed to the hook, so they
+// can be flagged or counted. Use it only as a last resort fallback.
+type EmergencyGenerator struct {
+	fepoch  int64
+	onIssue func(FlakeID)
+}
+
+// NewEmergencyGenerator returns an EmergencyGenerator for fepoch, onIssue
+// is called with every id issued and may be nil.
+func NewEmergencyGenerator(fepoch int64, onIssue func(FlakeID)) (*EmergencyGenerator, error) {
+	fepoch, err := checkConfig(0, fepoch)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmergencyGenerator{fepoch: fepoch, onIssue: onIssue}, nil
+}
+
+// NextIDErr returns a new id, or the error of reading crypto/rand.
+func (g *EmergencyGenerator) NextIDErr() (FlakeID, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0, err
+	}
+
+	ts, _ := getTsInfo()
+	ts -= g.fepoch
+	if ts < 0 {
+		ts = 0
+	}
+
+	random := FlakeID(binary.BigEndian.Uint64(b[:]))
+	id := FlakeID(ts&maxTimestamp)<<timestampLeftShift |
+		random&(1<<timestampLeftShift-1)
+
+	if g.onIssue != nil {
+		g.onIssue(id)
+	}
+
+	return id, nil
+}
+
+// NextID is like NextIDErr, it panics if crypto/rand fails.
+func (g *EmergencyGenerator) NextID() FlakeID {
+	id, err := g.NextIDErr()
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
@@ -0,0 +1,31 @@
+package flake
+
+import "testing"
+
+func TestEmergencyGenerator(t *testing.T) {
+	var flagged int
+	g, err := NewEmergencyGenerator(0, func(FlakeID) { flagged++ })
+	if err != nil {
+		t.Fatalf("Test emergency generator failed. Err: %s", err)
+	}
+
+	var _ IDGenerator = g
+
+	now, _ := getTsInfo()
+	// 1000 ids in a few milliseconds collide in a pair now and then on
+	// 23 random bits, more than a handful means the bits are not random
+	seen := make(map[FlakeID]bool)
+	for i := 0; i < 1000; i++ {
+		id := g.NextID()
+		seen[id] = true
+		if d := id.Timestamp() + DefaultEpoch - now; d < 0 || d > 1000 {
+			t.Fatalf("Test emergency generator failed, timestamp off by %d ms", d)
+		}
+	}
+	if len(seen) < 995 {
+		t.Errorf("Test emergency generator failed, %d duplicate IDs", 1000-len(seen))
+	}
+	if flagged != 1000 {
+		t.Errorf("Test emergency generator failed, hook called %d times", flagged)
+	}
+}
@@ -0,0 +1,58 @@
+package flake
+
+import "time"
+
+// ID is a FlakeID together with the epoch it was minted under, so its
+// time is read right wherever it is passed, also when ids of several
+// epochs meet in one process. The bit layout is fixed by this package,
+// only the epoch varies.
+type ID struct {
+	Value FlakeID
+	Name  string // registry name, empty if bound by a Generator
+	Epoch int64  // epoch in milliseconds, with the default applied
+}
+
+// Bind returns id as ID with the epoch of the generator.
+func (g *Generator) Bind(id FlakeID) ID {
+	return ID{Value: id, Epoch: g.fepoch}
+}
+
+// Bind returns id as ID with the epoch registered as name.
+func (r *Registry) Bind(name string, id FlakeID) (ID, error) {
+	fepoch, err := r.Epoch(name)
+	if err != nil {
+		return ID{}, err
+	}
+	return ID{Value: id, Name: name, Epoch: fepoch}, nil
+}
+
+// Bind binds with the default registry, see Registry.Bind.
+func Bind(name string, id FlakeID) (ID, error) {
+	return defaultRegistry.Bind(name, id)
+}
+
+// Time returns when the id was issued.
+func (id ID) Time() time.Time {
+	return id.Value.Time(id.Epoch)
+}
+
+// Age returns how long before now the id was issued.
+func (id ID) Age(now time.Time) time.Duration {
+	return id.Value.Age(id.Epoch, now)
+}
+
+// WorkerID returns the worker which issued the id.
+func (id ID) WorkerID() int64 {
+	return id.Value.WorkerID()
+}
+
+// Sequence returns the sequence of the id within its millisecond.
+func (id ID) Sequence() int64 {
+	return id.Value.Sequence()
+}
+
+// String returns the value as of FlakeID.ToString, the epoch is not
+// encoded and must be known to whoever decodes it, e.g. by name.
+func (id ID) String() string {
+	return id.Value.ToString()
+}
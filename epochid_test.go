@@ -0,0 +1,43 @@
+package flake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestID(t *testing.T) {
+	recent := time.Now().Add(-time.Hour).UnixMilli()
+	reg := NewRegistry()
+	reg.Register("id-recent", recent)
+
+	g, _ := NewGenerator(7, recent)
+	v := g.NextID()
+
+	for _, id := range []ID{g.Bind(v), mustBind(t, reg, "id-recent", v)} {
+		if d := time.Since(id.Time()); d < 0 || d > time.Second {
+			t.Errorf("Test ID failed, %+v time off by %s", id, d)
+		}
+		if id.WorkerID() != 7 || id.String() != v.ToString() {
+			t.Errorf("Test ID failed, got %+v", id)
+		}
+	}
+
+	// read with the default epoch the same value is years old, the
+	// mix-up ID guards against
+	dg, _ := NewGenerator(7, 0)
+	if d := dg.Bind(v).Age(time.Now()); d < 365*24*time.Hour {
+		t.Errorf("Test ID failed, default epoch age %s", d)
+	}
+
+	if _, err := reg.Bind("id-unknown", v); err == nil {
+		t.Errorf("Test Bind failed, unknown name accepted")
+	}
+}
+
+func mustBind(t *testing.T, reg *Registry, name string, v FlakeID) ID {
+	id, err := reg.Bind(name, v)
+	if err != nil {
+		t.Fatalf("Test Bind failed. Err: %s", err)
+	}
+	return id
+}
@@ -1,14 +1,15 @@
 package flake
 
 import (
-	"encoding/base64"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 )
 
 const (
+	timestampBits      = uint64(41)
+	maxTimestamp       = int64(-1) ^ (int64(-1) << timestampBits)
 	workerIDBits       = uint64(10)
 	maxWorkerID        = int64(-1) ^ (int64(-1) << workerIDBits)
 	sequenceBits       = uint64(13) // do not use standard 12 bits
@@ -17,163 +18,326 @@ const (
 	sequenceMask       = int64(-1) ^ (int64(-1) << sequenceBits)
 )
 
+// DefaultEpoch is the epoch in milliseconds used for a fepoch <= 0,
+// 2009-02-13T23:31:31.011Z.
+const DefaultEpoch = int64(1234567891011)
+
+var (
+	// ErrClockBackwards is reported when the clock is behind the last
+	// issued timestamp.
+	ErrClockBackwards = errors.New("clock is moving backwards")
+	// ErrEpochOverflow is reported when the time since fepoch no longer
+	// fits into the timestamp bits.
+	ErrEpochOverflow = errors.New("timestamp overflows the epoch")
+	// ErrWaitTimeout is reported when the sequence is exhausted and the
+	// next millisecond did not come within the max wait.
+	ErrWaitTimeout = errors.New("timed out waiting for next millisecond")
+)
+
 // FlakeID is short for (a simple) flake ID.
 type FlakeID uint64
 
 // id format:
 // timestampBits(41) | workerBits(10) | sequenceBits(13)
 
+// sequence is the last issued timestamp and sequence of one worker.
+type sequence struct {
+	seq int64
+	ts  int64 // the last timestamp in milliseconds
+}
+
 // Generator generates new FlakeID
 type Generator struct {
 	sync.Mutex
-	seq      int64
-	ts       int64 // the last timestamp in milliseconds
+	sequence
+	workers  map[int64]*sequence // other workers of NextIDWithWorker
 	fepoch   int64
 	workerID int64 // worker id  0 <= workerID <= maxWorkerID
+	maxWait  time.Duration
+	wait     WaitStrategy
+	spin     time.Duration // spin tail of WaitAdaptive
+	borrow   int64         // milliseconds WithBorrowAhead may run ahead
+	stats    Stats
+
+	onAbandon func(FlakeID)
+	recent    *ring[IssuedID]
+	clock     *clockLog
 }
 
-func NewGenerator(workerID, fepoch int64) (*Generator, error) {
+func NewGenerator(workerID, fepoch int64, opts ...Option) (*Generator, error) {
+	fepoch, err := checkConfig(workerID, fepoch)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Generator{
+		sequence: sequence{seq: -1, ts: -1},
+		fepoch:   fepoch,
+		workerID: workerID,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g, nil
+}
+
+// checkConfig validates workerID and fepoch, and returns fepoch with the
+// default applied.
+func checkConfig(workerID, fepoch int64) (int64, error) {
 	if workerID < 0 || workerID > maxWorkerID {
-		return nil, fmt.Errorf("worker id must be between 0 and %d, actual got %d",
+		return 0, fmt.Errorf("worker id must be between 0 and %d, actual got %d",
 			maxWorkerID, workerID)
 	}
 
 	now, _ := getTsInfo()
 	if now < fepoch {
-		return nil, fmt.Errorf("fepoch %d is moving backwards", fepoch)
+		return 0, fmt.Errorf("fepoch %d is moving backwards", fepoch)
 	}
 
+	return epochOrDefault(fepoch), nil
+}
+
+// epochOrDefault returns fepoch, or DefaultEpoch for fepoch <= 0.
+func epochOrDefault(fepoch int64) int64 {
 	if fepoch <= 0 {
-		// set default epoch 1234567891011
-		// 2009-02-13T23:31:31.011Z
-		fepoch = int64(1234567891011)
+		return DefaultEpoch
 	}
-
-	return &Generator{
-		seq:      -1,
-		ts:       -1,
-		fepoch:   fepoch,
-		workerID: workerID,
-	}, nil
+	return fepoch
 }
 
 // NextID returns the next unique id.
 func (g *Generator) NextID() FlakeID {
+	// no defer on the hot path, next does not panic
+	g.Lock()
+	id, _ := g.next(&g.sequence, g.workerID, false)
+	g.Unlock()
+	return id
+}
+
+// NextIDErr is like NextID, but returns ErrClockBackwards or
+// ErrEpochOverflow instead of silently issuing an id that may collide
+// or wrap around, and ErrWaitTimeout when WithMaxWait is exceeded.
+func (g *Generator) NextIDErr() (FlakeID, error) {
+	g.Lock()
+	id, err := g.next(&g.sequence, g.workerID, true)
+	g.Unlock()
+	return id, err
+}
+
+// NextIDWithWorker is like NextIDErr, but issues the id for workerID
+// instead of the generator's own worker. Every worker keeps its own
+// sequence, the generator's own worker shares it with NextID.
+func (g *Generator) NextIDWithWorker(workerID int64) (FlakeID, error) {
+	if workerID < 0 || workerID > maxWorkerID {
+		return 0, fmt.Errorf("worker id must be between 0 and %d, actual got %d",
+			maxWorkerID, workerID)
+	}
+
 	g.Lock()
 	defer g.Unlock()
 
-	ts, rem := getTsInfo()
-	lastTs := g.ts
-	seq := g.seq
-
-	switch {
-	// ts is never less than lastTs
-	case ts == lastTs:
-		seq = (seq + 1) & sequenceMask
-		if seq == 0 {
-			for ts <= lastTs {
-				time.Sleep(time.Duration(rem))
-				ts, rem = getTsInfo()
+	if workerID == g.workerID {
+		return g.next(&g.sequence, workerID, true)
+	}
+
+	s, ok := g.workers[workerID]
+	if !ok {
+		if g.workers == nil {
+			g.workers = make(map[int64]*sequence)
+		}
+		s = &sequence{seq: -1, ts: -1}
+		g.workers[workerID] = s
+	}
+
+	return g.next(s, workerID, true)
+}
+
+// next issues an id of workerID from s, strict enables the clock and
+// epoch checks. g must be locked, next unlocks it while waiting for the
+// next millisecond, so other callers, their max wait and Stats are not
+// held up by a stalled clock.
+func (g *Generator) next(s *sequence, workerID int64, strict bool) (FlakeID, error) {
+	var (
+		now      time.Time
+		ts, seq  int64
+		deadline time.Time // of WithMaxWait, from the first wait
+		waited   bool
+	)
+	for {
+		// one clock read serves the timestamp and the recent log
+		now = timeNow()
+		ts = now.UnixNano() / 1e6
+		lastTs := s.ts
+		seq = s.seq
+
+		clockTs := ts
+		if ts < lastTs && lastTs-ts <= g.borrow {
+			// still on milliseconds borrowed ahead of the clock
+			ts = lastTs
+		}
+
+		if g.clock != nil {
+			g.clock.check(now, ts, lastTs)
+		}
+
+		switch {
+		case ts == lastTs:
+			seq = (seq + 1) & sequenceMask
+			if seq != 0 {
+				break
+			}
+			if g.borrow > 0 && lastTs+1-clockTs <= g.borrow {
+				ts = g.borrowAhead(clockTs, lastTs, waited)
+				break
+			}
+
+			if strict && g.maxWait > 0 && deadline.IsZero() {
+				deadline = time.Now().Add(g.maxWait)
+			}
+			// wait for the millisecond after lastTs, or with borrowing
+			// until it is within reach, then start over as other
+			// callers may have issued meanwhile
+			if err := g.waitUnlocked(lastTs, lastTs-g.borrow, deadline); err != nil {
+				return 0, err
 			}
+			waited = true
+			continue
+		case ts < lastTs && strict:
+			return 0, fmt.Errorf("%w: last timestamp %d, now %d",
+				ErrClockBackwards, lastTs, ts)
+		default:
+			// without strict, a backwards clock is treated as a new
+			// millisecond
+			seq = 0
 		}
-	default:
-		seq = 0
+		break
 	}
 
-	g.ts = ts
-	g.seq = seq
+	if strict && ts-g.fepoch > maxTimestamp {
+		return 0, fmt.Errorf("%w: fepoch %d, now %d",
+			ErrEpochOverflow, g.fepoch, ts)
+	}
+
+	s.ts = ts
+	s.seq = seq
+	g.stats.Issued++
 
-	return FlakeID(
+	id := FlakeID(
 		(0 |
 			// timestamp
 			(ts-g.fepoch)<<timestampLeftShift) |
 			// workid
-			(g.workerID << workerIDShift) |
+			(workerID << workerIDShift) |
 			// sequence
 			seq,
 	)
+	if g.recent != nil {
+		g.recent.add(IssuedID{ID: id, At: now})
+	}
+
+	return id, nil
+}
+
+// waitUnlocked waits with g unlocked until the clock passes until, and
+// records the wait of the exhaustion at lastTs. g must be locked.
+func (g *Generator) waitUnlocked(lastTs, until int64, deadline time.Time) error {
+	spin := g.spin
+	g.Unlock()
+	start := time.Now()
+	spin, err := g.waitNext(until, deadline, spin)
+	d := time.Since(start)
+	g.Lock()
+
+	g.spin = spin
+	g.recordWait(lastTs, d)
+	return err
+}
+
+// borrowAhead returns the millisecond after lastTs, which is at most
+// g.borrow ahead of the clock at clockTs, and records the debt. The
+// exhaustion is counted unless its wait already was.
+func (g *Generator) borrowAhead(clockTs, lastTs int64, waited bool) int64 {
+	if !waited {
+		g.stats.Exhaustions++
+	}
+	g.stats.Borrowed++
+	if debt := time.Duration(lastTs+1-clockTs) * time.Millisecond; debt > g.stats.MaxDebt {
+		g.stats.MaxDebt = debt
+	}
+	return lastTs + 1
 }
 
 // GenMulti returns next n ids where n is given by parameter.
 func (g *Generator) GenMulti(n uint) []byte {
 	b := make([]byte, n*8)
 	for i := uint(0); i < n; i++ {
-		id := g.NextID()
-		off := i * 8
-		b[off+0] = byte(id >> 56)
-		b[off+1] = byte(id >> 48)
-		b[off+2] = byte(id >> 40)
-		b[off+3] = byte(id >> 32)
-		b[off+4] = byte(id >> 24)
-		b[off+5] = byte(id >> 16)
-		b[off+6] = byte(id >> 8)
-		b[off+7] = byte(id)
+		PutBytes(b[i*8:], g.NextID())
 	}
 	return b
 }
 
-// ToBytes convert id to byte array.
-func (id *FlakeID) ToBytes() []byte {
-	b := make([]byte, 8)
+// GenMultiIDs is like GenMulti, but returns typed ids and reports
+// errors like NextIDErr.
+func (g *Generator) GenMultiIDs(n uint) ([]FlakeID, error) {
+	g.Lock()
+	defer g.Unlock()
 
-	b[0] = byte(*id >> 56)
-	b[1] = byte(*id >> 48)
-	b[2] = byte(*id >> 40)
-	b[3] = byte(*id >> 32)
-	b[4] = byte(*id >> 24)
-	b[5] = byte(*id >> 16)
-	b[6] = byte(*id >> 8)
-	b[7] = byte(*id)
+	ids := make([]FlakeID, n)
+	for i := range ids {
+		id, err := g.next(&g.sequence, g.workerID, true)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
 
-	return b
+// ToBytes convert id to byte array.
+func (id *FlakeID) ToBytes() []byte {
+	return EncodeBytes(*id)
 }
 
 // ToString encode FlakeID to URL-compatible base64 string.
 func (id FlakeID) ToString() string {
-	bs := id.ToBytes()
-	return base64.URLEncoding.EncodeToString(bs)
+	return EncodeString(id)
 }
 
 // FromString decode URL-compatible base64 string to FlakeID.
 func (id *FlakeID) FromString(s string) error {
-	bs, err := base64.URLEncoding.DecodeString(s)
+	v, err := DecodeString[FlakeID](s)
 	if err != nil {
 		return err
 	}
 
-	*id = FlakeID(
-		(int64(bs[0]) << 56) |
-			(int64(bs[1]) << 48) |
-			(int64(bs[2]) << 40) |
-			(int64(bs[3]) << 32) |
-			(int64(bs[4]) << 24) |
-			(int64(bs[5]) << 16) |
-			(int64(bs[6]) << 8) |
-			int64(bs[7]),
-	)
+	*id = v
 
 	return nil
 }
 
 // MarshalJSON automatically convert id to string for JSON.
 func (id FlakeID) MarshalJSON() ([]byte, error) {
-	return json.Marshal(id.ToString())
+	return EncodeJSON(id)
 }
 
 // UnmarshalJSON convert JSON string to FlakeID.
 func (id *FlakeID) UnmarshalJSON(data []byte) error {
-	var s string
-	err := json.Unmarshal(data, &s)
-
+	v, err := DecodeJSON[FlakeID](data)
 	if err != nil {
 		return err
 	}
 
-	return id.FromString(s)
+	*id = v
+
+	return nil
 }
 
+// timeNow is replaced in tests to control the clock.
+var timeNow = time.Now
+
 func getTsInfo() (milliseconds, remain int64) {
-	nano := time.Now().UnixNano()
+	nano := timeNow().UnixNano()
 
 	return nano / 1e6, 1e6 - nano%1e6
 }
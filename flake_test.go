@@ -1,7 +1,11 @@
 package flake
 
 import (
+	"encoding/json"
+	"errors"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestFlakeGen(t *testing.T) {
@@ -20,3 +24,376 @@ func TestFlakeGen(t *testing.T) {
 		t.Errorf("Test flake ID generator failed, duplicate ID")
 	}
 }
+
+func TestFlakeGenErr(t *testing.T) {
+	g, err := NewGenerator(123, 0)
+	if err != nil {
+		t.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+
+	if _, err := g.NextIDErr(); err != nil {
+		t.Errorf("Test NextIDErr failed. Err: %s", err)
+	}
+
+	now, _ := getTsInfo()
+
+	g.ts = now + 1000
+	if _, err := g.NextIDErr(); !errors.Is(err, ErrClockBackwards) {
+		t.Errorf("Test NextIDErr failed, expected ErrClockBackwards, got %v", err)
+	}
+
+	g.ts = -1
+	g.fepoch = now - maxTimestamp - 1000
+	if _, err := g.NextIDErr(); !errors.Is(err, ErrEpochOverflow) {
+		t.Errorf("Test NextIDErr failed, expected ErrEpochOverflow, got %v", err)
+	}
+}
+
+func TestFlakeGenMaxWait(t *testing.T) {
+	g, err := NewGenerator(123, 0, WithMaxWait(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+
+	frozen := time.Now()
+	timeNow = func() time.Time { return frozen }
+	defer func() { timeNow = time.Now }()
+
+	if _, err := g.NextIDErr(); err != nil {
+		t.Errorf("Test NextIDErr failed. Err: %s", err)
+	}
+
+	g.seq = sequenceMask
+	if _, err := g.NextIDErr(); !errors.Is(err, ErrWaitTimeout) {
+		t.Errorf("Test WithMaxWait failed, expected ErrWaitTimeout, got %v", err)
+	}
+}
+
+func TestFlakeGenWaitStrategy(t *testing.T) {
+	for _, s := range []WaitStrategy{WaitAdaptive, WaitSleep, WaitSpin, WaitYield} {
+		g, err := NewGenerator(123, 0, WithWaitStrategy(s))
+		if err != nil {
+			t.Fatalf("Test flake ID generator failed. Err: %s", err)
+		}
+
+		last := g.NextID()
+		for i := 0; i < 3*int(sequenceMask+1); i++ {
+			id := g.NextID()
+			if id <= last {
+				t.Fatalf("Test wait strategy %s failed, %d after %d", s, id, last)
+			}
+			last = id
+		}
+	}
+}
+
+// BenchmarkWaitStrategy issues past sequence exhaustion so the ns/op
+// includes the time spent waiting for the next millisecond.
+func BenchmarkWaitStrategy(b *testing.B) {
+	for _, s := range []WaitStrategy{WaitAdaptive, WaitSleep, WaitSpin, WaitYield} {
+		b.Run(s.String(), func(b *testing.B) {
+			g, err := NewGenerator(123, 0, WithWaitStrategy(s))
+			if err != nil {
+				b.Fatalf("Test flake ID generator failed. Err: %s", err)
+			}
+			for i := 0; i < b.N; i++ {
+				g.NextID()
+			}
+		})
+	}
+}
+
+func TestFlakeGenFrozenClock(t *testing.T) {
+	nano, moved := time.Now().UnixNano(), int32(0)
+	timeNow = func() time.Time {
+		if atomic.LoadInt32(&moved) == 1 {
+			return time.Unix(0, nano+int64(time.Millisecond))
+		}
+		return time.Unix(0, nano)
+	}
+	defer func() { timeNow = time.Now }()
+
+	g, err := NewGenerator(123, 0, WithMaxWait(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+	first := g.NextID()
+	g.Lock()
+	g.seq = sequenceMask
+	g.Unlock()
+
+	// NextID has no deadline and waits for the clock, but must not hold
+	// up the max wait of NextIDErr or Stats meanwhile
+	issued := make(chan FlakeID)
+	go func() { issued <- g.NextID() }()
+	time.Sleep(5 * time.Millisecond)
+
+	start := time.Now()
+	if _, err := g.NextIDErr(); !errors.Is(err, ErrWaitTimeout) {
+		t.Errorf("Test NextIDErr failed, expected timeout, got %v", err)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("Test NextIDErr failed, waited %s for a 10ms max wait", d)
+	}
+	if st := g.Stats(); st.Issued != 1 {
+		t.Errorf("Test Stats failed, issued %d during the stall", st.Issued)
+	}
+
+	atomic.StoreInt32(&moved, 1)
+	select {
+	case id := <-issued:
+		if id <= first {
+			t.Errorf("Test NextID failed, got %d after %d", id, first)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Test NextID failed, still waiting after the clock moved")
+	}
+}
+
+func TestFlakeGenWithWorker(t *testing.T) {
+	g, err := NewGenerator(123, 0)
+	if err != nil {
+		t.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+
+	if _, err := g.NextIDWithWorker(maxWorkerID + 1); err == nil {
+		t.Errorf("Test NextIDWithWorker failed, invalid worker accepted")
+	}
+
+	seen := make(map[FlakeID]bool)
+	for i := 0; i < 1000; i++ {
+		for _, w := range []int64{123, 7, 8} {
+			id, err := g.NextIDWithWorker(w)
+			if err != nil {
+				t.Fatalf("Test NextIDWithWorker failed. Err: %s", err)
+			}
+			if got := int64(id>>workerIDShift) & maxWorkerID; got != w {
+				t.Fatalf("Test NextIDWithWorker failed, worker %d, expected %d", got, w)
+			}
+			if seen[id] {
+				t.Fatalf("Test NextIDWithWorker failed, duplicate ID")
+			}
+			seen[id] = true
+		}
+		id := g.NextID()
+		if seen[id] {
+			t.Fatalf("Test NextIDWithWorker failed, duplicate ID with NextID")
+		}
+		seen[id] = true
+	}
+}
+
+func TestFlakeGenStats(t *testing.T) {
+	g, err := NewGenerator(123, 0)
+	if err != nil {
+		t.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+
+	// the clock moves on after the first three reads
+	frozen, calls := time.Now(), 0
+	timeNow = func() time.Time {
+		calls++
+		if calls > 3 {
+			return frozen.Add(time.Millisecond)
+		}
+		return frozen
+	}
+	defer func() { timeNow = time.Now }()
+
+	g.NextID()
+	g.seq = sequenceMask
+	g.NextID()
+
+	st := g.Stats()
+	if st.Issued != 2 {
+		t.Errorf("Test Stats failed, issued %d, expected 2", st.Issued)
+	}
+	if st.Exhaustions != 1 || st.Waited <= 0 || st.MaxWaited != st.Waited {
+		t.Errorf("Test Stats failed, unexpected wait counters %+v", st)
+	}
+
+	g.NextID()
+	if d := g.Stats().Sub(st); d.Issued != 1 || d.Exhaustions != 0 {
+		t.Errorf("Test Stats.Sub failed, got %+v", d)
+	}
+}
+
+func TestFlakeGenDescribe(t *testing.T) {
+	g, err := NewGenerator(123, 0, WithMaxWait(time.Second))
+	if err != nil {
+		t.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+
+	d := g.Describe()
+	if d.WorkerID != 123 || d.MaxWait != time.Second || d.WaitStrategy != WaitAdaptive {
+		t.Errorf("Test Describe failed, got %+v", d)
+	}
+	if d.Epoch.UnixMilli() != DefaultEpoch {
+		t.Errorf("Test Describe failed, epoch %s", d.Epoch)
+	}
+	if got := d.Exhausts.Year(); got != 2078 {
+		t.Errorf("Test Describe failed, exhausts in %d, expected 2078", got)
+	}
+	t.Logf("Generator: %s", d)
+}
+
+func TestFlakeGenSchema(t *testing.T) {
+	g, err := NewGenerator(123, 0)
+	if err != nil {
+		t.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+
+	data, err := json.Marshal(g.Schema())
+	if err != nil {
+		t.Fatalf("Test Schema failed. Err: %s", err)
+	}
+
+	want := `{"version":1,"epoch_ms":1234567891011,"fields":[` +
+		`{"name":"timestamp","shift":23,"bits":41},` +
+		`{"name":"worker","shift":13,"bits":10},` +
+		`{"name":"sequence","shift":0,"bits":13}],` +
+		`"string_encoding":"base64url"}`
+	if string(data) != want {
+		t.Errorf("Test Schema failed, got %s", data)
+	}
+}
+
+func TestFlakeGenMultiIDs(t *testing.T) {
+	g, err := NewGenerator(123, 0)
+	if err != nil {
+		t.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+
+	ids, err := g.GenMultiIDs(10000)
+	if err != nil || len(ids) != 10000 {
+		t.Fatalf("Test GenMultiIDs failed, got %d ids, err: %v", len(ids), err)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("Test GenMultiIDs failed, %d after %d", ids[i], ids[i-1])
+		}
+	}
+
+	b := g.GenMulti(1)
+	if id, _ := DecodeBytes[FlakeID](b); id <= ids[len(ids)-1] {
+		t.Errorf("Test GenMulti failed, %d after %d", id, ids[len(ids)-1])
+	}
+}
+
+// fakeClock advances timeNow by 1µs per read, so the NextID benchmarks
+// measure the issuing path and not the wait on sequence exhaustion.
+func fakeClock(b *testing.B) {
+	nano := time.Now().UnixNano()
+	timeNow = func() time.Time {
+		return time.Unix(0, atomic.AddInt64(&nano, int64(time.Microsecond)))
+	}
+	b.Cleanup(func() { timeNow = time.Now })
+}
+
+func BenchmarkNextID(b *testing.B) {
+	fakeClock(b)
+	g, err := NewGenerator(123, 0)
+	if err != nil {
+		b.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		g.NextID()
+	}
+}
+
+func BenchmarkNextIDWithRecent(b *testing.B) {
+	fakeClock(b)
+	g, err := NewGenerator(123, 0, WithRecent(64))
+	if err != nil {
+		b.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		g.NextID()
+	}
+}
+
+func BenchmarkNextIDParallel(b *testing.B) {
+	fakeClock(b)
+	g, err := NewGenerator(123, 0)
+	if err != nil {
+		b.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			g.NextID()
+		}
+	})
+}
+
+func TestNextIDAllocs(t *testing.T) {
+	g, err := NewGenerator(123, 0, WithRecent(64))
+	if err != nil {
+		t.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+	if n := testing.AllocsPerRun(1000, func() { g.NextID() }); n != 0 {
+		t.Errorf("Test NextID failed, %v allocations per id", n)
+	}
+	if n := testing.AllocsPerRun(1000, func() { g.NextIDErr() }); n != 0 {
+		t.Errorf("Test NextIDErr failed, %v allocations per id", n)
+	}
+}
+
+func TestWithBorrowAheadRounding(t *testing.T) {
+	for _, c := range []struct {
+		max  time.Duration
+		want time.Duration
+	}{
+		{0, 0}, {-time.Millisecond, 0}, {time.Microsecond, time.Millisecond},
+		{time.Millisecond, time.Millisecond}, {1500 * time.Microsecond, 2 * time.Millisecond},
+	} {
+		g, _ := NewGenerator(123, 0, WithBorrowAhead(c.max))
+		if got := g.Describe().BorrowAhead; got != c.want {
+			t.Errorf("Test WithBorrowAhead failed, %s gives %s, expected %s", c.max, got, c.want)
+		}
+	}
+}
+
+func TestFlakeGenBorrowAhead(t *testing.T) {
+	frozen := time.Now()
+	timeNow = func() time.Time { return frozen }
+	defer func() { timeNow = time.Now }()
+
+	g, err := NewGenerator(123, 0, WithBorrowAhead(2*time.Millisecond),
+		WithMaxWait(time.Millisecond))
+	if err != nil {
+		t.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+
+	// two milliseconds may be borrowed on a frozen clock, then the
+	// sequence waits for it
+	seen := make(map[FlakeID]bool)
+	var last FlakeID
+	for i := 0; i < 3*int(sequenceMask+1); i++ {
+		id, err := g.NextIDErr()
+		if err != nil {
+			t.Fatalf("Test NextIDErr failed at %d. Err: %s", i, err)
+		}
+		if seen[id] || id <= last {
+			t.Fatalf("Test NextIDErr failed, id %d repeated or out of order", id)
+		}
+		seen[id], last = true, id
+	}
+	if _, err := g.NextIDErr(); !errors.Is(err, ErrWaitTimeout) {
+		t.Errorf("Test NextIDErr failed, borrowed past the limit, err: %v", err)
+	}
+
+	st := g.Stats()
+	if st.Borrowed != 2 || st.MaxDebt != 2*time.Millisecond {
+		t.Errorf("Test Stats failed, unexpected borrow counters %+v", st)
+	}
+
+	// once the clock moves on, the debt is paid back before new
+	// milliseconds are used
+	frozen = frozen.Add(time.Millisecond)
+	if id, err := g.NextIDErr(); err != nil || id <= last {
+		t.Errorf("Test NextIDErr failed, got %d after %d, err: %v", id, last, err)
+	}
+}
@@ -0,0 +1,14 @@
+module github.com/liuchong/go-flake/flakegorm
+
+go 1.18
+
+require (
+	github.com/liuchong/go-flake v0.0.0-20261014043940-9def9a8b6d11
+	gorm.io/gorm v1.31.2
+)
+
+require (
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	golang.org/x/text v0.20.0 // indirect
+)
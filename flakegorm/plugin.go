@@ -0,0 +1,99 @@
+// Package flakegorm is a GORM plugin which assigns FlakeID primary keys
+// on create.
+//
+//	db.Use(flakegorm.New(gen))
+//
+// Every model whose primary key field has type flake.FlakeID then gets
+// a new id before insert, unless the key is already set.
+package flakegorm
+
+import (
+	"reflect"
+
+	flake "github.com/liuchong/go-flake"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var flakeIDType = reflect.TypeOf(flake.FlakeID(0))
+
+// Plugin assigns FlakeID primary keys, see New.
+type Plugin struct {
+	gen    flake.IDGenerator
+	tables map[string]flake.IDGenerator
+}
+
+// Option configures a Plugin.
+type Option func(*Plugin)
+
+// WithTable uses gen instead of the shared generator for the models
+// stored in table.
+func WithTable(table string, gen flake.IDGenerator) Option {
+	return func(p *Plugin) {
+		p.tables[table] = gen
+	}
+}
+
+// New returns a Plugin issuing ids from gen, which is shared by all
+// models not configured with WithTable. gen may be nil if only the
+// tables of WithTable get ids.
+func New(gen flake.IDGenerator, opts ...Option) *Plugin {
+	p := &Plugin{
+		gen:    gen,
+		tables: make(map[string]flake.IDGenerator),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Name implements gorm.Plugin.
+func (p *Plugin) Name() string {
+	return "flake"
+}
+
+// Initialize implements gorm.Plugin.
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	return db.Callback().Create().Before("gorm:create").Register("flake:assign_id", p.assign)
+}
+
+func (p *Plugin) assign(db *gorm.DB) {
+	s := db.Statement
+	if db.Error != nil || s.Schema == nil {
+		return
+	}
+
+	gen, ok := p.tables[s.Schema.Table]
+	if !ok {
+		gen = p.gen
+	}
+	if gen == nil {
+		return
+	}
+
+	for _, f := range s.Schema.PrimaryFields {
+		if f.FieldType != flakeIDType {
+			continue
+		}
+
+		switch rv := reflect.Indirect(s.ReflectValue); rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < rv.Len(); i++ {
+				set(db, f, reflect.Indirect(rv.Index(i)), gen)
+			}
+		case reflect.Struct:
+			set(db, f, rv, gen)
+		}
+	}
+}
+
+func set(db *gorm.DB, f *schema.Field, rv reflect.Value, gen flake.IDGenerator) {
+	ctx := db.Statement.Context
+	if _, zero := f.ValueOf(ctx, rv); !zero {
+		return
+	}
+	if err := f.Set(ctx, rv, gen.NextID()); err != nil {
+		db.AddError(err)
+	}
+}
@@ -0,0 +1,55 @@
+package flakegorm
+
+import (
+	"testing"
+
+	flake "github.com/liuchong/go-flake"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+type order struct {
+	ID   flake.FlakeID
+	Name string
+}
+
+type user struct {
+	ID   flake.FlakeID
+	Name string
+}
+
+type counter struct {
+	ID   uint64
+	Name string
+}
+
+func TestPlugin(t *testing.T) {
+	shared, _ := flake.NewGenerator(1, 0)
+	users, _ := flake.NewGenerator(2, 0)
+
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("Test gorm open failed. Err: %s", err)
+	}
+	if err := db.Use(New(shared, WithTable("users", users))); err != nil {
+		t.Fatalf("Test plugin failed. Err: %s", err)
+	}
+
+	o := order{Name: "o"}
+	if err := db.Create(&o).Error; err != nil || o.ID.WorkerID() != 1 {
+		t.Errorf("Test plugin failed, order id %d, err: %v", o.ID, err)
+	}
+
+	us := []user{{Name: "a"}, {ID: 42, Name: "b"}}
+	if err := db.Create(&us).Error; err != nil {
+		t.Fatalf("Test plugin failed. Err: %s", err)
+	}
+	if us[0].ID.WorkerID() != 2 || us[1].ID != 42 {
+		t.Errorf("Test plugin failed, user ids %d %d", us[0].ID, us[1].ID)
+	}
+
+	c := counter{Name: "c"}
+	if err := db.Create(&c).Error; err != nil || c.ID != 0 {
+		t.Errorf("Test plugin failed, non-flake id %d assigned, err: %v", c.ID, err)
+	}
+}
@@ -0,0 +1,112 @@
+// Package flakenats serves and fetches flake ids over NATS
+// request/reply.
+//
+// A request with an empty payload asks for one id, a decimal payload
+// for that many ids. The reply holds the ids as 8 bytes big-endian each,
+// or an empty payload with the Flake-Error header on failure.
+package flakenats
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	flake "github.com/liuchong/go-flake"
+	"github.com/nats-io/nats.go"
+)
+
+// MaxBatch is the most ids served for one request.
+const MaxBatch = 10000
+
+// ErrorHeader is the reply header holding the error of a failed request.
+const ErrorHeader = "Flake-Error"
+
+// Serve answers id requests on subject from gen. Issuers sharing queue
+// split the requests, each must use its own worker id.
+func Serve(nc *nats.Conn, subject, queue string, gen *flake.Generator) (*nats.Subscription, error) {
+	return nc.QueueSubscribe(subject, queue, func(msg *nats.Msg) {
+		data, err := handle(gen, msg.Data)
+
+		reply := nats.NewMsg(msg.Reply)
+		if err != nil {
+			reply.Header.Set(ErrorHeader, err.Error())
+		} else {
+			reply.Data = data
+		}
+		msg.RespondMsg(reply)
+	})
+}
+
+func handle(gen *flake.Generator, req []byte) ([]byte, error) {
+	n := 1
+	if len(req) > 0 {
+		var err error
+		n, err = strconv.Atoi(string(req))
+		if err != nil || n < 1 || n > MaxBatch {
+			return nil, fmt.Errorf("count must be between 1 and %d, actual got %q",
+				MaxBatch, req)
+		}
+	}
+
+	ids, err := gen.GenMultiIDs(uint(n))
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, len(ids)*8)
+	for i, id := range ids {
+		flake.PutBytes(b[i*8:], id)
+	}
+	return b, nil
+}
+
+// Client requests ids from issuers started with Serve.
+type Client struct {
+	nc      *nats.Conn
+	subject string
+	timeout time.Duration
+}
+
+// NewClient returns a Client requesting on subject, waiting at most
+// timeout for each reply.
+func NewClient(nc *nats.Conn, subject string, timeout time.Duration) *Client {
+	return &Client{nc: nc, subject: subject, timeout: timeout}
+}
+
+// NextID requests one id.
+func (c *Client) NextID() (flake.FlakeID, error) {
+	ids, err := c.request(nil, 1)
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// NextIDs requests n ids, 1 <= n <= MaxBatch.
+func (c *Client) NextIDs(n int) ([]flake.FlakeID, error) {
+	return c.request([]byte(strconv.Itoa(n)), n)
+}
+
+func (c *Client) request(req []byte, n int) ([]flake.FlakeID, error) {
+	msg, err := c.nc.Request(c.subject, req, c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	return decode(msg, n)
+}
+
+func decode(msg *nats.Msg, n int) ([]flake.FlakeID, error) {
+	if s := msg.Header.Get(ErrorHeader); s != "" {
+		return nil, errors.New(s)
+	}
+	if len(msg.Data) != n*8 {
+		return nil, fmt.Errorf("reply must be %d bytes, actual got %d", n*8, len(msg.Data))
+	}
+
+	ids := make([]flake.FlakeID, n)
+	for i := range ids {
+		ids[i], _ = flake.DecodeBytes[flake.FlakeID](msg.Data[i*8 : i*8+8])
+	}
+	return ids, nil
+}
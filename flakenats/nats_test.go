@@ -0,0 +1,44 @@
+package flakenats
+
+import (
+	"testing"
+
+	flake "github.com/liuchong/go-flake"
+	"github.com/nats-io/nats.go"
+)
+
+func TestHandle(t *testing.T) {
+	gen, _ := flake.NewGenerator(1, 0)
+
+	for _, tc := range []struct {
+		req string
+		n   int
+	}{{"", 1}, {"100", 100}, {"10000", MaxBatch}} {
+		data, err := handle(gen, []byte(tc.req))
+		if err != nil {
+			t.Fatalf("Test handle failed. Err: %s", err)
+		}
+
+		ids, err := decode(&nats.Msg{Data: data}, tc.n)
+		if err != nil {
+			t.Fatalf("Test decode failed. Err: %s", err)
+		}
+		for i := 1; i < len(ids); i++ {
+			if ids[i] <= ids[i-1] {
+				t.Fatalf("Test handle failed, %d after %d", ids[i], ids[i-1])
+			}
+		}
+	}
+
+	for _, req := range []string{"0", "-1", "x", "10001"} {
+		if _, err := handle(gen, []byte(req)); err == nil {
+			t.Errorf("Test handle failed, count %q accepted", req)
+		}
+	}
+
+	msg := nats.NewMsg("")
+	msg.Header.Set(ErrorHeader, "boom")
+	if _, err := decode(msg, 1); err == nil || err.Error() != "boom" {
+		t.Errorf("Test decode failed, got err %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+// Package flakeresp serves flake ids over the Redis protocol (RESP), so
+// any Redis client can fetch them:
+//
+//	FLAKE.NEXTID        -> "1234567890123456789"
+//	FLAKE.NEXTIDS 100   -> array of 100 ids
+//
+// Ids are replied as decimal bulk strings, RESP integers are signed and
+// would overflow for ids past 2^63.
+package flakeresp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	flake "github.com/liuchong/go-flake"
+)
+
+// MaxBatch is the most ids served for one FLAKE.NEXTIDS.
+const MaxBatch = 10000
+
+// maxLine is the longest inline command or header line accepted, longer
+// lines are a protocol error instead of growing the buffer unbounded.
+const maxLine = 4096
+
+// Serve accepts connections on l and answers them from gen until l is
+// closed.
+func Serve(l net.Listener, gen *flake.Generator) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go ServeConn(conn, gen)
+	}
+}
+
+// ServeConn answers commands on conn until it is closed or QUIT.
+func ServeConn(conn net.Conn, gen *flake.Generator) {
+	defer conn.Close()
+
+	r := bufio.NewReaderSize(conn, maxLine)
+	w := bufio.NewWriter(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			if err != io.EOF {
+				writeError(w, err.Error())
+				w.Flush()
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		quit := exec(w, gen, args)
+		if w.Flush() != nil || quit {
+			return
+		}
+	}
+}
+
+func exec(w *bufio.Writer, gen *flake.Generator, args []string) (quit bool) {
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		w.WriteString("+PONG\r\n")
+	case "QUIT":
+		w.WriteString("+OK\r\n")
+		return true
+	case "FLAKE.NEXTID":
+		if len(args) != 1 {
+			writeError(w, "wrong number of arguments for 'flake.nextid'")
+			break
+		}
+		id, err := gen.NextIDErr()
+		if err != nil {
+			writeError(w, err.Error())
+			break
+		}
+		writeID(w, id)
+	case "FLAKE.NEXTIDS":
+		if len(args) != 2 {
+			writeError(w, "wrong number of arguments for 'flake.nextids'")
+			break
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 || n > MaxBatch {
+			writeError(w, fmt.Sprintf("count must be between 1 and %d", MaxBatch))
+			break
+		}
+		ids, err := gen.GenMultiIDs(uint(n))
+		if err != nil {
+			writeError(w, err.Error())
+			break
+		}
+		fmt.Fprintf(w, "*%d\r\n", len(ids))
+		for _, id := range ids {
+			writeID(w, id)
+		}
+	default:
+		writeError(w, fmt.Sprintf("unknown command '%s'", args[0]))
+	}
+	return false
+}
+
+func writeID(w *bufio.Writer, id flake.FlakeID) {
+	s := strconv.FormatUint(uint64(id), 10)
+	fmt.Fprintf(w, "$%d\r\n%s\r\n", len(s), s)
+}
+
+// crlf strips line breaks from error messages, which may echo client
+// input and must not end the reply early.
+var crlf = strings.NewReplacer("\r", " ", "\n", " ")
+
+func writeError(w *bufio.Writer, msg string) {
+	fmt.Fprintf(w, "-ERR %s\r\n", crlf.Replace(msg))
+}
+
+// readCommand reads a command as array of bulk strings, or as inline
+// command for telnet-style clients.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return strings.Fields(line), nil
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 0 || n > 1024 {
+		return nil, errors.New("protocol error: invalid multibulk length")
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		line, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(line, "$") {
+			return nil, errors.New("protocol error: expected '$'")
+		}
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 || size > 512 {
+			return nil, errors.New("protocol error: invalid bulk length")
+		}
+
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		return "", errors.New("protocol error: too big inline request")
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
@@ -0,0 +1,86 @@
+package flakeresp
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	flake "github.com/liuchong/go-flake"
+)
+
+func TestServeConn(t *testing.T) {
+	gen, _ := flake.NewGenerator(1, 0)
+
+	client, server := net.Pipe()
+	defer client.Close()
+	go ServeConn(server, gen)
+
+	r := bufio.NewReader(client)
+	send := func(cmd string) {
+		if _, err := client.Write([]byte(cmd)); err != nil {
+			t.Fatalf("Test RESP write failed. Err: %s", err)
+		}
+	}
+	line := func() string {
+		s, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Test RESP read failed. Err: %s", err)
+		}
+		return strings.TrimRight(s, "\r\n")
+	}
+
+	send("PING\r\n")
+	if s := line(); s != "+PONG" {
+		t.Errorf("Test PING failed, got %q", s)
+	}
+
+	send("*1\r\n$12\r\nFLAKE.NEXTID\r\n")
+	if s := line(); !strings.HasPrefix(s, "$") {
+		t.Fatalf("Test FLAKE.NEXTID failed, got %q", s)
+	}
+	last, err := strconv.ParseUint(line(), 10, 64)
+	if err != nil {
+		t.Fatalf("Test FLAKE.NEXTID failed. Err: %s", err)
+	}
+
+	send("*2\r\n$13\r\nflake.nextids\r\n$3\r\n100\r\n")
+	if s := line(); s != "*100" {
+		t.Fatalf("Test FLAKE.NEXTIDS failed, got %q", s)
+	}
+	for i := 0; i < 100; i++ {
+		line()
+		id, err := strconv.ParseUint(line(), 10, 64)
+		if err != nil || id <= last {
+			t.Fatalf("Test FLAKE.NEXTIDS failed, %d after %d, err: %v", id, last, err)
+		}
+		last = id
+	}
+
+	send("FLAKE.NEXTIDS 0\r\n")
+	if s := line(); !strings.HasPrefix(s, "-ERR") {
+		t.Errorf("Test FLAKE.NEXTIDS failed, expected error, got %q", s)
+	}
+
+	send("GET x\r\n")
+	if s := line(); !strings.HasPrefix(s, "-ERR unknown command") {
+		t.Errorf("Test unknown command failed, got %q", s)
+	}
+
+	// a bulk command name with a line break is echoed on one line
+	send("*1\r\n$9\r\nA\r\n+OK\r\nB\r\n")
+	if s := line(); !strings.HasPrefix(s, "-ERR unknown command") {
+		t.Errorf("Test unknown command failed, got %q", s)
+	}
+	send("PING\r\n")
+	if s := line(); s != "+PONG" {
+		t.Errorf("Test unknown command failed, reply injected, got %q", s)
+	}
+
+	// an endless inline line is cut off instead of buffered
+	go client.Write([]byte(strings.Repeat("x", 2*maxLine)))
+	if s := line(); !strings.HasPrefix(s, "-ERR protocol error") {
+		t.Errorf("Test long inline failed, got %q", s)
+	}
+}
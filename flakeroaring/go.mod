@@ -0,0 +1,13 @@
+module github.com/liuchong/go-flake/flakeroaring
+
+go 1.18
+
+require (
+	github.com/RoaringBitmap/roaring v1.9.4
+	github.com/liuchong/go-flake v0.0.0-20261014043940-9def9a8b6d11
+)
+
+require (
+	github.com/bits-and-blooms/bitset v1.12.0 // indirect
+	github.com/mschoch/smat v0.2.0 // indirect
+)
@@ -0,0 +1,101 @@
+// Package flakeroaring maps FlakeID sets to roaring64 bitmaps, which
+// hold hundreds of millions of ids in little memory. Ids sort by time,
+// so time ranges are plain value ranges of the bitmap.
+package flakeroaring
+
+import (
+	"time"
+
+	"github.com/RoaringBitmap/roaring/roaring64"
+	flake "github.com/liuchong/go-flake"
+)
+
+// FromIDs returns a bitmap holding ids.
+func FromIDs(ids []flake.FlakeID) *roaring64.Bitmap {
+	b := roaring64.New()
+	AddIDs(b, ids)
+	return b
+}
+
+// AddIDs adds ids to b.
+func AddIDs(b *roaring64.Bitmap, ids []flake.FlakeID) {
+	vs := make([]uint64, len(ids))
+	for i, id := range ids {
+		vs[i] = uint64(id)
+	}
+	b.AddMany(vs)
+}
+
+// ToIDs returns the ids of b, sorted.
+func ToIDs(b *roaring64.Bitmap) []flake.FlakeID {
+	vs := b.ToArray()
+	ids := make([]flake.FlakeID, len(vs))
+	for i, v := range vs {
+		ids[i] = flake.FlakeID(v)
+	}
+	return ids
+}
+
+// Contains reports whether id is in b.
+func Contains(b *roaring64.Bitmap, id flake.FlakeID) bool {
+	return b.Contains(uint64(id))
+}
+
+// TimeRange returns the ids of b issued in [from, to), fepoch is the
+// epoch in milliseconds of the generators which issued them, <= 0 for
+// flake.DefaultEpoch.
+func TimeRange(b *roaring64.Bitmap, fepoch int64, from, to time.Time) *roaring64.Bitmap {
+	lo, hi := floor(fepoch, from), floor(fepoch, to)
+
+	// walk the ids in the window only, a range bitmap of the window
+	// would hold 2^23 values per millisecond
+	var vs []uint64
+	it := b.Iterator()
+	it.AdvanceIfNeeded(lo)
+	for it.HasNext() {
+		v := it.PeekNext()
+		if v >= hi {
+			break
+		}
+		vs = append(vs, v)
+		it.Next()
+	}
+
+	r := roaring64.New()
+	r.AddMany(vs)
+	return r
+}
+
+// CountRange returns how many ids of b were issued in [from, to).
+func CountRange(b *roaring64.Bitmap, fepoch int64, from, to time.Time) uint64 {
+	lo, hi := floor(fepoch, from), floor(fepoch, to)
+	if lo >= hi {
+		return 0
+	}
+
+	// Rank counts the values <= x
+	n := b.Rank(hi - 1)
+	if lo > 0 {
+		n -= b.Rank(lo - 1)
+	}
+	return n
+}
+
+// floor returns the least id value issued at t.
+func floor(fepoch int64, t time.Time) uint64 {
+	if fepoch <= 0 {
+		fepoch = flake.DefaultEpoch
+	}
+
+	ms := t.UnixMilli() - fepoch
+	if ms <= 0 {
+		return 0
+	}
+
+	id, err := flake.Compose(ms, 0, 0)
+	if err != nil {
+		// past the timestamp bits
+		return ^uint64(0)
+	}
+	return uint64(id)
+}
@@ -0,0 +1,73 @@
+package flakeroaring
+
+import (
+	"testing"
+	"time"
+
+	flake "github.com/liuchong/go-flake"
+)
+
+func TestTimeRange(t *testing.T) {
+	const fepoch = flake.DefaultEpoch
+	base := time.UnixMilli(fepoch + 1000000)
+
+	var ids []flake.FlakeID
+	for ms := int64(0); ms < 100; ms++ {
+		for w := int64(0); w < 3; w++ {
+			id, _ := flake.Compose(1000000+ms, w, ms%5)
+			ids = append(ids, id)
+		}
+	}
+
+	b := FromIDs(ids)
+	if b.GetCardinality() != 300 || !Contains(b, ids[42]) {
+		t.Fatalf("Test FromIDs failed, cardinality %d", b.GetCardinality())
+	}
+	if got := ToIDs(b); len(got) != 300 || got[0] != ids[0] {
+		t.Errorf("Test ToIDs failed, got %d ids", len(got))
+	}
+
+	from, to := base.Add(10*time.Millisecond), base.Add(20*time.Millisecond)
+	r := TimeRange(b, fepoch, from, to)
+	if r.GetCardinality() != 30 {
+		t.Errorf("Test TimeRange failed, got %d ids, expected 30", r.GetCardinality())
+	}
+	for _, id := range ToIDs(r) {
+		if at := id.Time(fepoch); at.Before(from) || !at.Before(to) {
+			t.Errorf("Test TimeRange failed, id at %s", at)
+		}
+	}
+	if n := CountRange(b, fepoch, from, to); n != 30 {
+		t.Errorf("Test CountRange failed, got %d, expected 30", n)
+	}
+	if n := CountRange(b, 0, from, to); n != 30 {
+		t.Errorf("Test CountRange failed, default epoch got %d, expected 30", n)
+	}
+	if n := CountRange(b, fepoch, time.Unix(0, 0), base.Add(time.Hour)); n != 300 {
+		t.Errorf("Test CountRange failed, got %d, expected 300", n)
+	}
+}
+
+func TestTimeRangeLongWindow(t *testing.T) {
+	const fepoch = flake.DefaultEpoch
+	base := time.UnixMilli(fepoch + 1000000)
+
+	var ids []flake.FlakeID
+	for i := int64(0); i < 100; i++ {
+		id, _ := flake.Compose(1000000+i*time.Minute.Milliseconds(), 1, 0)
+		ids = append(ids, id)
+	}
+	b := FromIDs(ids)
+
+	// an hour covers 2^23 * 3.6e6 possible ids, but only 60 are in b
+	from, to := base, base.Add(time.Hour)
+	if r := TimeRange(b, fepoch, from, to); r.GetCardinality() != 60 {
+		t.Errorf("Test TimeRange failed, got %d ids, expected 60", r.GetCardinality())
+	}
+	if n := CountRange(b, fepoch, from, to); n != 60 {
+		t.Errorf("Test CountRange failed, got %d, expected 60", n)
+	}
+	if r := TimeRange(b, fepoch, to, from); !r.IsEmpty() {
+		t.Errorf("Test TimeRange failed, reversed window not empty")
+	}
+}
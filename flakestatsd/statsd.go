@@ -0,0 +1,136 @@
+// Package flakestatsd sends Generator counters to statsd or the Datadog
+// agent.
+//
+// Every flush sends the counters accumulated since the last one:
+//
+//	<prefix>.issued:<n>|c
+//	<prefix>.exhaustions:<n>|c
+//	<prefix>.wait_ms:<ms>|c
+//	<prefix>.max_wait_ms:<ms>|g
+//	<prefix>.reserved:<n>|c
+//	<prefix>.abandoned:<n>|c
+//	<prefix>.borrowed_ms:<n>|c
+//	<prefix>.max_debt_ms:<ms>|g
+//
+// max_wait_ms is the longest single wait and max_debt_ms the furthest
+// ids ran ahead of the clock with flake.WithBorrowAhead, both since the
+// generator started.
+package flakestatsd
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	flake "github.com/liuchong/go-flake"
+)
+
+// Emitter sends the counters of a Generator over UDP.
+type Emitter struct {
+	sync.Mutex
+	conn   net.Conn
+	gen    *flake.Generator
+	prefix string
+	tags   string // Datadog tag suffix, e.g. "|#env:prod"
+	last   flake.Stats
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// Option configures an Emitter.
+type Option func(*Emitter)
+
+// WithTags adds Datadog tags like "env:prod" to every metric.
+func WithTags(tags ...string) Option {
+	return func(e *Emitter) {
+		if len(tags) > 0 {
+			e.tags = "|#" + strings.Join(tags, ",")
+		}
+	}
+}
+
+// New returns an Emitter sending the counters of gen to the statsd
+// server at addr, e.g. "127.0.0.1:8125", with metric names prefixed.
+func New(addr, prefix string, gen *flake.Generator, opts ...Option) (*Emitter, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Emitter{
+		conn:   conn,
+		gen:    gen,
+		prefix: prefix,
+		last:   gen.Stats(),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e, nil
+}
+
+// Flush sends the counters accumulated since the last flush.
+func (e *Emitter) Flush() error {
+	e.Lock()
+	defer e.Unlock()
+
+	st := e.gen.Stats()
+	d := st.Sub(e.last)
+	e.last = st
+
+	var b bytes.Buffer
+	e.metric(&b, "issued", d.Issued, "c")
+	e.metric(&b, "exhaustions", d.Exhaustions, "c")
+	e.metric(&b, "wait_ms", d.Waited.Milliseconds(), "c")
+	e.metric(&b, "max_wait_ms", d.MaxWaited.Milliseconds(), "g")
+	e.metric(&b, "reserved", d.Reserved, "c")
+	e.metric(&b, "abandoned", d.Abandoned, "c")
+	e.metric(&b, "borrowed_ms", d.Borrowed, "c")
+	e.metric(&b, "max_debt_ms", d.MaxDebt.Milliseconds(), "g")
+
+	_, err := e.conn.Write(bytes.TrimSuffix(b.Bytes(), []byte("\n")))
+	return err
+}
+
+func (e *Emitter) metric(b *bytes.Buffer, name string, v interface{}, typ string) {
+	fmt.Fprintf(b, "%s.%s:%d|%s%s\n", e.prefix, name, v, typ, e.tags)
+}
+
+// Start flushes every interval until Stop.
+func (e *Emitter) Start(interval time.Duration) {
+	e.stop = make(chan struct{})
+	e.done = make(chan struct{})
+
+	go func() {
+		defer close(e.done)
+
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				e.Flush()
+			case <-e.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops a started Emitter, flushes a last time and closes the
+// connection.
+func (e *Emitter) Stop() error {
+	if e.stop != nil {
+		close(e.stop)
+		<-e.done
+	}
+
+	err := e.Flush()
+	if cerr := e.conn.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
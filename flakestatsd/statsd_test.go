@@ -0,0 +1,43 @@
+package flakestatsd
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	flake "github.com/liuchong/go-flake"
+)
+
+func TestEmitter(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Test statsd listen failed. Err: %s", err)
+	}
+	defer pc.Close()
+
+	gen, _ := flake.NewGenerator(1, 0)
+	e, err := New(pc.LocalAddr().String(), "flake", gen, WithTags("env:test"))
+	if err != nil {
+		t.Fatalf("Test New failed. Err: %s", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		gen.NextID()
+	}
+	if err := e.Stop(); err != nil {
+		t.Fatalf("Test Stop failed. Err: %s", err)
+	}
+
+	buf := make([]byte, 1024)
+	pc.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Test statsd read failed. Err: %s", err)
+	}
+
+	lines := strings.Split(string(buf[:n]), "\n")
+	if len(lines) != 8 || lines[0] != "flake.issued:3|c|#env:test" {
+		t.Errorf("Test Flush failed, got %q", buf[:n])
+	}
+}
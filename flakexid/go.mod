@@ -0,0 +1,8 @@
+module github.com/liuchong/go-flake/flakexid
+
+go 1.18
+
+require (
+	github.com/liuchong/go-flake v0.0.0-20261014043940-9def9a8b6d11
+	github.com/rs/xid v1.6.0
+)
@@ -0,0 +1,71 @@
+// Package flakexid converts between FlakeIDs and rs/xid ids, for
+// migrations between the two libraries.
+//
+// An xid is seconds(4) | machine(3) | pid(2) | counter(3). ToXID puts
+// the worker into the machine bytes with pid 0, and the millisecond
+// within the second and the sequence into the counter, so FromXID
+// restores those ids exactly. Native xids only keep rough ordering:
+// the machine and pid are hashed into the worker bits, and the counter
+// fills the millisecond and sequence bits.
+package flakexid
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+
+	flake "github.com/liuchong/go-flake"
+	"github.com/rs/xid"
+)
+
+const (
+	sequenceBits = 13
+	sequenceMask = 1<<sequenceBits - 1
+	workerMax    = 1<<10 - 1
+)
+
+// ToXID converts id to an xid, fepoch is the epoch in milliseconds of
+// the generator which issued it, <= 0 for flake.DefaultEpoch.
+func ToXID(id flake.FlakeID, fepoch int64) xid.ID {
+	var x xid.ID
+
+	ms := id.Time(fepoch).UnixMilli()
+	binary.BigEndian.PutUint32(x[0:4], uint32(ms/1000))
+
+	w := id.WorkerID()
+	x[4], x[5], x[6] = 0, byte(w>>8), byte(w)
+	// pid x[7:9] stays 0
+
+	counter := uint32(ms%1000)<<sequenceBits | uint32(id.Sequence())
+	x[9], x[10], x[11] = byte(counter>>16), byte(counter>>8), byte(counter)
+
+	return x
+}
+
+// FromXID converts x to a FlakeID of the given epoch in milliseconds,
+// <= 0 for flake.DefaultEpoch.
+func FromXID(x xid.ID, fepoch int64) (flake.FlakeID, error) {
+	if fepoch <= 0 {
+		fepoch = flake.DefaultEpoch
+	}
+
+	counter := uint32(x[9])<<16 | uint32(x[10])<<8 | uint32(x[11])
+	ms := int64(counter>>sequenceBits) % 1000
+	seq := int64(counter & sequenceMask)
+
+	var worker int64
+	if m := int64(x[4])<<16 | int64(x[5])<<8 | int64(x[6]); x.Pid() == 0 && m <= workerMax {
+		worker = m
+	} else {
+		h := fnv.New32a()
+		h.Write(x[4:9])
+		worker = int64(h.Sum32() % (workerMax + 1))
+	}
+
+	ts := int64(binary.BigEndian.Uint32(x[0:4]))*1000 + ms - fepoch
+	if ts < 0 {
+		return 0, fmt.Errorf("xid time %s is before the epoch", x.Time())
+	}
+
+	return flake.Compose(ts, worker, seq)
+}
@@ -0,0 +1,49 @@
+package flakexid
+
+import (
+	"testing"
+	"time"
+
+	flake "github.com/liuchong/go-flake"
+	"github.com/rs/xid"
+)
+
+func TestRoundTrip(t *testing.T) {
+	const fepoch = 1234567891011
+	g, _ := flake.NewGenerator(1000, fepoch)
+	dg, _ := flake.NewGenerator(1000, 0)
+
+	for i := 0; i < 1000; i++ {
+		if id := dg.NextID(); ToXID(id, 0) != ToXID(id, flake.DefaultEpoch) {
+			t.Fatalf("Test ToXID failed, epoch 0 is not the default")
+		}
+
+		id := g.NextID()
+		x := ToXID(id, fepoch)
+		if got := x.Time().Unix(); got != id.Time(fepoch).Unix() {
+			t.Fatalf("Test ToXID failed, time %d, expected %d", got, id.Time(fepoch).Unix())
+		}
+
+		back, err := FromXID(x, fepoch)
+		if err != nil || back != id {
+			t.Fatalf("Test FromXID failed, got %d, expected %d, err: %v", back, id, err)
+		}
+	}
+}
+
+func TestFromNativeXID(t *testing.T) {
+	now := time.Now()
+	x := xid.NewWithTime(now)
+
+	id, err := FromXID(x, 0)
+	if err != nil {
+		t.Fatalf("Test FromXID failed. Err: %s", err)
+	}
+	if d := id.Time(0).Sub(now.Truncate(time.Second)); d < 0 || d >= time.Second {
+		t.Errorf("Test FromXID failed, time off by %s", d)
+	}
+
+	if _, err := FromXID(x, now.Add(time.Hour).UnixMilli()); err == nil {
+		t.Errorf("Test FromXID failed, xid before epoch accepted")
+	}
+}
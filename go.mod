@@ -1,3 +1,3 @@
 module github.com/liuchong/go-flake
 
-go 1.11.9
+go 1.18
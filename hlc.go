@@ -0,0 +1,90 @@
+package flake
+
+import (
+	"fmt"
+	"sync"
+)
+
+// HLCGenerator issues ids from a hybrid logical clock: the timestamp is
+// the max of the physical clock and every id observed, the sequence
+// is the logical counter. An id issued after Observe(remote) is always
+// greater than remote, so ids respect causality across nodes. A busy
+// or ratcheted clock may put timestamps ahead of the physical time.
+//
+// Ids use the same layout as Generator, nodes must share the epoch.
+type HLCGenerator struct {
+	sync.Mutex
+	l        int64 // logical time, milliseconds since fepoch
+	c        int64 // logical counter of l, -1 if none issued yet
+	fepoch   int64
+	workerID int64
+}
+
+// NewHLCGenerator is like NewGenerator for a HLCGenerator.
+func NewHLCGenerator(workerID, fepoch int64) (*HLCGenerator, error) {
+	fepoch, err := checkConfig(workerID, fepoch)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HLCGenerator{
+		l:        -1,
+		c:        -1,
+		fepoch:   fepoch,
+		workerID: workerID,
+	}, nil
+}
+
+// NextID returns the next id, it never goes backwards even when the
+// physical clock does.
+func (g *HLCGenerator) NextID() FlakeID {
+	g.Lock()
+	defer g.Unlock()
+
+	pt, _ := getTsInfo()
+	pt -= g.fepoch
+
+	if pt > g.l {
+		g.l, g.c = pt, 0
+	} else {
+		g.c++
+		if g.c > sequenceMask {
+			g.l, g.c = g.l+1, 0
+		}
+	}
+
+	return FlakeID(g.l<<timestampLeftShift | g.workerID<<workerIDShift | g.c)
+}
+
+// Observe ratchets the clock forward on receipt of remote, which must
+// have been issued with the same epoch.
+func (g *HLCGenerator) Observe(remote FlakeID) {
+	rts, rw, rseq := remote.Timestamp(), remote.WorkerID(), remote.Sequence()
+
+	// (ts, c) is the least pair for which our id is greater than remote
+	var ts, c int64
+	switch {
+	case g.workerID > rw:
+		ts, c = rts, 0
+	case g.workerID == rw && rseq < sequenceMask:
+		ts, c = rts, rseq+1
+	default:
+		ts, c = rts+1, 0
+	}
+
+	g.Lock()
+	defer g.Unlock()
+
+	// NextID increments the counter first, so keep one below
+	if ts > g.l || (ts == g.l && c-1 > g.c) {
+		g.l, g.c = ts, c-1
+	}
+}
+
+// String returns the logical clock for debugging.
+func (g *HLCGenerator) String() string {
+	g.Lock()
+	defer g.Unlock()
+
+	return fmt.Sprintf("hlc(worker %d, l %d, c %d)", g.workerID, g.l, g.c)
+}
@@ -0,0 +1,33 @@
+package flake
+
+import "testing"
+
+func TestHLCGenerator(t *testing.T) {
+	g, err := NewHLCGenerator(5, 0)
+	if err != nil {
+		t.Fatalf("Test HLC generator failed. Err: %s", err)
+	}
+
+	last := g.NextID()
+	future := last.Timestamp() + 60000
+	for _, w := range []int64{4, 5, 6} {
+		for _, seq := range []int64{0, sequenceMask} {
+			remote, _ := Compose(future, w, seq)
+			g.Observe(remote)
+			id := g.NextID()
+			if id <= remote || id <= last {
+				t.Errorf("Test Observe failed, %d after remote %d and last %d", id, remote, last)
+			}
+			last = id
+		}
+		future++
+	}
+
+	// old ids never move the clock back
+	old, _ := Compose(1, 1, 1)
+	g.Observe(old)
+	if id := g.NextID(); id <= last {
+		t.Errorf("Test Observe failed, %d after %d", id, last)
+	}
+	t.Logf("Clock: %s", g)
+}
@@ -0,0 +1,70 @@
+package flake
+
+import (
+	"fmt"
+	"time"
+)
+
+// Compose builds the id from its fields, timestamp counts milliseconds
+// since the epoch of the generator.
+func Compose(timestamp, workerID, seq int64) (FlakeID, error) {
+	if timestamp < 0 || timestamp > maxTimestamp {
+		return 0, fmt.Errorf("timestamp must be between 0 and %d, actual got %d",
+			maxTimestamp, timestamp)
+	}
+	if workerID < 0 || workerID > maxWorkerID {
+		return 0, fmt.Errorf("worker id must be between 0 and %d, actual got %d",
+			maxWorkerID, workerID)
+	}
+	if seq < 0 || seq > sequenceMask {
+		return 0, fmt.Errorf("sequence must be between 0 and %d, actual got %d",
+			sequenceMask, seq)
+	}
+
+	return FlakeID(timestamp<<timestampLeftShift | workerID<<workerIDShift | seq), nil
+}
+
+// Timestamp returns the milliseconds since epoch the id was issued at.
+func (id FlakeID) Timestamp() int64 {
+	return int64(id >> timestampLeftShift)
+}
+
+// WorkerID returns the worker which issued the id.
+func (id FlakeID) WorkerID() int64 {
+	return int64(id>>workerIDShift) & maxWorkerID
+}
+
+// Sequence returns the sequence of the id within its millisecond.
+func (id FlakeID) Sequence() int64 {
+	return int64(id) & sequenceMask
+}
+
+// Time returns when the id was issued, fepoch is the epoch in
+// milliseconds of the generator which issued it, <= 0 for DefaultEpoch
+// as with NewGenerator.
+func (id FlakeID) Time(fepoch int64) time.Time {
+	return time.UnixMilli(epochOrDefault(fepoch) + id.Timestamp())
+}
+
+// Age returns how long before now the id was issued, fepoch is the epoch
+// in milliseconds of the generator which issued it, as with Time.
+func (id FlakeID) Age(fepoch int64, now time.Time) time.Duration {
+	return now.Sub(id.Time(fepoch))
+}
+
+// SkewBetween returns the apparent clock difference of the workers of a
+// and b, of the same epoch. With knownOrder, a was issued before b, e.g.
+// b was issued in reply to a, and the result is how far the clock of b
+// is behind the one of a at least, zero if the ids agree with the order.
+// Without, it is the signed time from a to b, the skew of ids issued at
+// about the same moment.
+func SkewBetween(a, b FlakeID, knownOrder bool) time.Duration {
+	d := time.Duration(b.Timestamp()-a.Timestamp()) * time.Millisecond
+	if !knownOrder {
+		return d
+	}
+	if d >= 0 {
+		return 0
+	}
+	return -d
+}
@@ -0,0 +1,106 @@
+package flake
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCompose(t *testing.T) {
+	id, err := Compose(maxTimestamp, 123, sequenceMask)
+	if err != nil {
+		t.Fatalf("Test Compose failed. Err: %s", err)
+	}
+	if id.Timestamp() != maxTimestamp || id.WorkerID() != 123 || id.Sequence() != sequenceMask {
+		t.Errorf("Test Compose failed, got %d|%d|%d", id.Timestamp(), id.WorkerID(), id.Sequence())
+	}
+
+	if _, err := Compose(0, maxWorkerID+1, 0); err == nil {
+		t.Errorf("Test Compose failed, invalid worker accepted")
+	}
+}
+
+// TestVectors checks the cross-language vectors in testdata, they are
+// generated by `flakectl vectors`.
+func TestVectors(t *testing.T) {
+	data, err := os.ReadFile("testdata/vectors.json")
+	if err != nil {
+		t.Fatalf("Test vectors failed. Err: %s", err)
+	}
+
+	var file struct {
+		Vectors []struct {
+			Timestamp int64  `json:"timestamp"`
+			Worker    int64  `json:"worker"`
+			Sequence  int64  `json:"sequence"`
+			ID        string `json:"id"`
+			Hex       string `json:"hex"`
+			Base64URL string `json:"base64url"`
+		} `json:"vectors"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("Test vectors failed. Err: %s", err)
+	}
+
+	for _, v := range file.Vectors {
+		id, err := Compose(v.Timestamp, v.Worker, v.Sequence)
+		if err != nil {
+			t.Fatalf("Test vectors failed. Err: %s", err)
+		}
+		if s := strconv.FormatUint(uint64(id), 10); s != v.ID {
+			t.Errorf("Test vectors failed, id %s, expected %s", s, v.ID)
+		}
+		if s := hex.EncodeToString(id.ToBytes()); s != v.Hex {
+			t.Errorf("Test vectors failed, hex %s, expected %s", s, v.Hex)
+		}
+		if s := id.ToString(); s != v.Base64URL {
+			t.Errorf("Test vectors failed, base64url %s, expected %s", s, v.Base64URL)
+		}
+	}
+}
+
+func TestTimeDefaultEpoch(t *testing.T) {
+	g, _ := NewGenerator(1, 0)
+	id := g.NextID()
+
+	if d := time.Since(id.Time(0)); d < 0 || d > time.Second {
+		t.Errorf("Test Time failed, default epoch id off by %s", d)
+	}
+	if !id.Time(-1).Equal(id.Time(DefaultEpoch)) {
+		t.Errorf("Test Time failed, negative epoch not the default")
+	}
+}
+
+func TestSkewBetween(t *testing.T) {
+	a, _ := Compose(1000, 1, 0)
+	b, _ := Compose(1250, 2, 0)
+
+	if d := SkewBetween(a, b, false); d != 250*time.Millisecond {
+		t.Errorf("Test SkewBetween failed, got %s", d)
+	}
+	if d := SkewBetween(b, a, false); d != -250*time.Millisecond {
+		t.Errorf("Test SkewBetween failed, got %s", d)
+	}
+	if d := SkewBetween(a, b, true); d != 0 {
+		t.Errorf("Test SkewBetween failed, ordered ids skewed by %s", d)
+	}
+	if d := SkewBetween(b, a, true); d != 250*time.Millisecond {
+		t.Errorf("Test SkewBetween failed, got %s", d)
+	}
+}
+
+func TestAge(t *testing.T) {
+	const fepoch = 1234567891011
+	id, _ := Compose(1000, 1, 0)
+
+	now := time.UnixMilli(fepoch + 3000)
+	if d := id.Age(fepoch, now); d != 2*time.Second {
+		t.Errorf("Test Age failed, got %s", d)
+	}
+	if d := id.Age(0, now); d != 2*time.Second {
+		t.Errorf("Test Age failed, default epoch got %s", d)
+	}
+}
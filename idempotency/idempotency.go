@@ -0,0 +1,148 @@
+// Package idempotency uses FlakeIDs as idempotency keys of HTTP requests.
+//
+// The client mints a key per logical operation and sends it with every
+// retry, the server rejects keys which are malformed, too old, or already
+// seen. As the key is a FlakeID, its age is known without a lookup.
+package idempotency
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	flake "github.com/liuchong/go-flake"
+)
+
+// Header is the request header carrying the key.
+const Header = "Idempotency-Key"
+
+var (
+	// ErrMissing is returned when the request carries no key.
+	ErrMissing = errors.New("missing idempotency key")
+	// ErrInvalid is returned for keys which are not a FlakeID.
+	ErrInvalid = errors.New("invalid idempotency key")
+	// ErrExpired is returned for keys older than the max age, or issued
+	// in the future.
+	ErrExpired = errors.New("idempotency key expired")
+	// ErrDuplicate is returned for keys already seen.
+	ErrDuplicate = errors.New("duplicate idempotency key")
+)
+
+// Mint returns a new key from gen.
+func Mint(gen flake.IDGenerator) flake.FlakeID {
+	return gen.NextID()
+}
+
+// Set sets key as the idempotency header of h.
+func Set(h http.Header, key flake.FlakeID) {
+	h.Set(Header, key.ToString())
+}
+
+// Extract returns the key of r.
+func Extract(r *http.Request) (flake.FlakeID, error) {
+	s := r.Header.Get(Header)
+	if s == "" {
+		return 0, ErrMissing
+	}
+
+	var key flake.FlakeID
+	if err := key.FromString(s); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return key, nil
+}
+
+// Store remembers the keys seen by the server.
+type Store interface {
+	// Seen records key for ttl and reports whether it was recorded
+	// already. It must be atomic for concurrent requests of one key.
+	Seen(key flake.FlakeID, ttl time.Duration) (bool, error)
+}
+
+// Validator checks the keys of incoming requests.
+type Validator struct {
+	fepoch int64
+	maxAge time.Duration
+	store  Store
+}
+
+// NewValidator returns a Validator accepting keys issued with epoch
+// fepoch, <= 0 for flake.DefaultEpoch as with flake.NewGenerator, within
+// maxAge. store may be nil to skip the duplicate check.
+func NewValidator(fepoch int64, maxAge time.Duration, store Store) (*Validator, error) {
+	if maxAge <= 0 {
+		return nil, errors.New("max age must be positive")
+	}
+	return &Validator{fepoch: fepoch, maxAge: maxAge, store: store}, nil
+}
+
+// skew is how far in the future a key may be issued, for clients with
+// a clock slightly ahead of the server.
+const skew = time.Second
+
+// Check extracts and validates the key of r, and records it in the
+// store. Once a key expires the store may forget it, as it is rejected
+// by age from then on.
+func (v *Validator) Check(r *http.Request) (flake.FlakeID, error) {
+	key, err := Extract(r)
+	if err != nil {
+		return 0, err
+	}
+
+	age := key.Age(v.fepoch, timeNow())
+	if age > v.maxAge || age < -skew {
+		return key, fmt.Errorf("%w: issued %s ago", ErrExpired, age)
+	}
+
+	if v.store != nil {
+		seen, err := v.store.Seen(key, v.maxAge-age+skew)
+		if err != nil {
+			return key, err
+		}
+		if seen {
+			return key, ErrDuplicate
+		}
+	}
+
+	return key, nil
+}
+
+// MemoryStore is a Store for a single server.
+type MemoryStore struct {
+	sync.Mutex
+	keys    map[flake.FlakeID]time.Time
+	sweepAt int // drop expired keys once this many are stored
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{keys: make(map[flake.FlakeID]time.Time)}
+}
+
+// Seen implements Store. Expired keys are dropped whenever the store
+// doubled in size, to keep Seen amortized constant time.
+func (s *MemoryStore) Seen(key flake.FlakeID, ttl time.Duration) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	now := timeNow()
+	if exp, ok := s.keys[key]; ok && now.Before(exp) {
+		return true, nil
+	}
+	if len(s.keys) >= s.sweepAt {
+		for k, exp := range s.keys {
+			if !now.Before(exp) {
+				delete(s.keys, k)
+			}
+		}
+		s.sweepAt = 2*len(s.keys) + 64
+	}
+
+	s.keys[key] = now.Add(ttl)
+	return false, nil
+}
+
+// timeNow is replaced in tests to control the clock.
+var timeNow = time.Now
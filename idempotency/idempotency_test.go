@@ -0,0 +1,79 @@
+package idempotency
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	flake "github.com/liuchong/go-flake"
+)
+
+const fepoch = flake.DefaultEpoch
+
+func TestCheck(t *testing.T) {
+	gen, _ := flake.NewGenerator(1, fepoch)
+	v, err := NewValidator(fepoch, time.Hour, NewMemoryStore())
+	if err != nil {
+		t.Fatalf("Test NewValidator failed. Err: %s", err)
+	}
+
+	key := Mint(gen)
+	r := httptest.NewRequest("POST", "/", nil)
+	Set(r.Header, key)
+
+	if got, err := v.Check(r); err != nil || got != key {
+		t.Errorf("Test Check failed, got %d, err: %v", got, err)
+	}
+	if _, err := v.Check(r); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Test Check failed, retry not reported as duplicate, err: %v", err)
+	}
+
+	r = httptest.NewRequest("POST", "/", nil)
+	if _, err := v.Check(r); !errors.Is(err, ErrMissing) {
+		t.Errorf("Test Check failed, missing key, err: %v", err)
+	}
+	r.Header.Set(Header, "not a key")
+	if _, err := v.Check(r); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Test Check failed, invalid key, err: %v", err)
+	}
+
+	old, _ := flake.Compose(key.Timestamp()-2*time.Hour.Milliseconds(), 1, 0)
+	Set(r.Header, old)
+	if _, err := v.Check(r); !errors.Is(err, ErrExpired) {
+		t.Errorf("Test Check failed, old key accepted, err: %v", err)
+	}
+}
+
+func TestCheckDefaultEpoch(t *testing.T) {
+	gen, _ := flake.NewGenerator(1, 0)
+	v, err := NewValidator(0, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("Test NewValidator failed. Err: %s", err)
+	}
+
+	r := httptest.NewRequest("POST", "/", nil)
+	Set(r.Header, Mint(gen))
+	if _, err := v.Check(r); err != nil {
+		t.Errorf("Test Check failed, default epoch key rejected, err: %v", err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Now()
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	s := NewMemoryStore()
+	if seen, _ := s.Seen(1, time.Minute); seen {
+		t.Errorf("Test Seen failed, new key seen")
+	}
+	if seen, _ := s.Seen(1, time.Minute); !seen {
+		t.Errorf("Test Seen failed, key not seen")
+	}
+
+	now = now.Add(time.Minute)
+	if seen, _ := s.Seen(1, time.Minute); seen {
+		t.Errorf("Test Seen failed, expired key seen")
+	}
+}
@@ -0,0 +1,22 @@
+//go:build !tinygo && !flake_tiny
+
+package flake
+
+import "encoding/json"
+
+// EncodeJSON encode id to a JSON string holding its base64 form.
+func EncodeJSON[T Uint64](id T) ([]byte, error) {
+	return json.Marshal(EncodeString(id))
+}
+
+// DecodeJSON decode a JSON string holding the base64 form to id.
+func DecodeJSON[T Uint64](data []byte) (T, error) {
+	var s string
+	err := json.Unmarshal(data, &s)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return DecodeString[T](s)
+}
@@ -0,0 +1,30 @@
+//go:build tinygo || flake_tiny
+
+package flake
+
+import (
+	"bytes"
+	"errors"
+)
+
+// EncodeJSON encode id to a JSON string holding its base64 form.
+// Base64 needs no escaping, so plain quoting is enough.
+func EncodeJSON[T Uint64](id T) ([]byte, error) {
+	s := EncodeString(id)
+	b := make([]byte, 0, len(s)+2)
+	b = append(b, '"')
+	b = append(b, s...)
+	return append(b, '"'), nil
+}
+
+// DecodeJSON decode a JSON string holding the base64 form to id,
+// escape sequences are not supported.
+func DecodeJSON[T Uint64](data []byte) (T, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' ||
+		bytes.IndexByte(data, '\\') >= 0 {
+		return 0, errors.New("id must be a JSON string without escapes")
+	}
+
+	return DecodeString[T](string(data[1 : len(data)-1]))
+}
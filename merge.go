@@ -0,0 +1,109 @@
+package flake
+
+import "container/heap"
+
+type mergeItem struct {
+	id  FlakeID
+	src int
+}
+
+// mergeHeap orders ids by timestamp, then worker, then sequence. The
+// fields are laid out high to low, so that is the order of the value.
+type mergeHeap []mergeItem
+
+func (h mergeHeap) Len() int            { return len(h) }
+func (h mergeHeap) Less(i, j int) bool  { return h[i].id < h[j].id }
+func (h mergeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *mergeHeap) Push(x interface{}) { *h = append(*h, x.(mergeItem)) }
+func (h *mergeHeap) Pop() interface{} {
+	old := *h
+	it := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return it
+}
+
+// Merge merges time-ordered id streams, e.g. one per worker, into one
+// time-ordered stream. The result is closed after all streams are
+// closed and drained, or once done is closed, e.g. ctx.Done(), for
+// consumers which stop reading early.
+func Merge(done <-chan struct{}, streams ...<-chan FlakeID) <-chan FlakeID {
+	out := make(chan FlakeID)
+
+	go func() {
+		defer close(out)
+
+		// recv is false once done is closed, or s is closed and drained
+		recv := func(s <-chan FlakeID) (FlakeID, bool, bool) {
+			select {
+			case id, ok := <-s:
+				return id, ok, true
+			case <-done:
+				return 0, false, false
+			}
+		}
+
+		h := make(mergeHeap, 0, len(streams))
+		for i, s := range streams {
+			id, ok, alive := recv(s)
+			if !alive {
+				return
+			}
+			if ok {
+				h = append(h, mergeItem{id, i})
+			}
+		}
+		heap.Init(&h)
+
+		for h.Len() > 0 {
+			it := h[0]
+			select {
+			case out <- it.id:
+			case <-done:
+				return
+			}
+
+			id, ok, alive := recv(streams[it.src])
+			if !alive {
+				return
+			}
+			if ok {
+				h[0].id = id
+				heap.Fix(&h, 0)
+			} else {
+				heap.Pop(&h)
+			}
+		}
+	}()
+
+	return out
+}
+
+// MergeSorted is like Merge for slices.
+func MergeSorted(lists ...[]FlakeID) []FlakeID {
+	n := 0
+	h := make(mergeHeap, 0, len(lists))
+	pos := make([]int, len(lists))
+	for i, l := range lists {
+		n += len(l)
+		if len(l) > 0 {
+			h = append(h, mergeItem{l[0], i})
+			pos[i] = 1
+		}
+	}
+	heap.Init(&h)
+
+	out := make([]FlakeID, 0, n)
+	for h.Len() > 0 {
+		it := h[0]
+		out = append(out, it.id)
+		if l := lists[it.src]; pos[it.src] < len(l) {
+			h[0].id = l[pos[it.src]]
+			pos[it.src]++
+			heap.Fix(&h, 0)
+		} else {
+			heap.Pop(&h)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,91 @@
+package flake
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	var lists [][]FlakeID
+	var all []FlakeID
+	for _, w := range []int64{3, 1, 2} {
+		var l []FlakeID
+		for ts := int64(0); ts < 50; ts += w {
+			id, _ := Compose(ts, w, ts%7)
+			l = append(l, id)
+		}
+		lists = append(lists, l)
+		all = append(all, l...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+
+	got := MergeSorted(lists...)
+	if len(got) != len(all) {
+		t.Fatalf("Test MergeSorted failed, got %d ids, expected %d", len(got), len(all))
+	}
+	for i := range got {
+		if got[i] != all[i] {
+			t.Fatalf("Test MergeSorted failed at %d, got %d, expected %d", i, got[i], all[i])
+		}
+	}
+
+	var chans []<-chan FlakeID
+	for _, l := range lists {
+		c := make(chan FlakeID, len(l))
+		for _, id := range l {
+			c <- id
+		}
+		close(c)
+		chans = append(chans, c)
+	}
+	i := 0
+	for id := range Merge(nil, chans...) {
+		if id != all[i] {
+			t.Fatalf("Test Merge failed at %d, got %d, expected %d", i, id, all[i])
+		}
+		i++
+	}
+	if i != len(all) {
+		t.Errorf("Test Merge failed, got %d ids, expected %d", i, len(all))
+	}
+}
+
+func TestMergeDone(t *testing.T) {
+	// endless streams, the merge only ends by done
+	var chans []<-chan FlakeID
+	for w := int64(0); w < 3; w++ {
+		c := make(chan FlakeID)
+		go func(w int64) {
+			for ts := int64(0); ; ts++ {
+				id, _ := Compose(ts, w, 0)
+				c <- id
+			}
+		}(w)
+		chans = append(chans, c)
+	}
+
+	done := make(chan struct{})
+	out := Merge(done, chans...)
+	for i := 0; i < 10; i++ {
+		<-out
+	}
+	close(done)
+
+	select {
+	case <-drained(out):
+	case <-time.After(time.Second):
+		t.Errorf("Test Merge failed, still running after done")
+	}
+}
+
+// drained is closed once c is closed.
+func drained(c <-chan FlakeID) <-chan struct{} {
+	d := make(chan struct{})
+	go func() {
+		for range c {
+		}
+		close(d)
+	}()
+	return d
+}
@@ -0,0 +1,49 @@
+package flake
+
+import (
+	"fmt"
+	"sync"
+)
+
+// IDGenerator is anything issuing FlakeIDs, *Generator implements it.
+type IDGenerator interface {
+	NextID() FlakeID
+}
+
+// MonotonicChecker wraps an IDGenerator and checks every id is greater
+// than the one before, to catch clock or configuration regressions.
+type MonotonicChecker struct {
+	sync.Mutex
+	gen         IDGenerator
+	last        FlakeID
+	started     bool
+	onViolation func(prev, next FlakeID)
+}
+
+// NewMonotonicChecker wraps gen, onViolation is called with the previous
+// and the offending id, nil means panic.
+func NewMonotonicChecker(gen IDGenerator, onViolation func(prev, next FlakeID)) *MonotonicChecker {
+	return &MonotonicChecker{
+		gen:         gen,
+		onViolation: onViolation,
+	}
+}
+
+// NextID returns the next id of the wrapped generator. The call is held
+// under the checker lock, so ids are checked in the order issued.
+func (c *MonotonicChecker) NextID() FlakeID {
+	c.Lock()
+	defer c.Unlock()
+
+	id := c.gen.NextID()
+	if c.started && id <= c.last {
+		if c.onViolation == nil {
+			panic(fmt.Sprintf("flake: id %d is not greater than previous %d", id, c.last))
+		}
+		c.onViolation(c.last, id)
+	}
+	c.last = id
+	c.started = true
+
+	return id
+}
@@ -0,0 +1,33 @@
+package flake
+
+import "testing"
+
+type fixedGen []FlakeID
+
+func (g *fixedGen) NextID() FlakeID {
+	id := (*g)[0]
+	*g = (*g)[1:]
+	return id
+}
+
+func TestMonotonicChecker(t *testing.T) {
+	var violations [][2]FlakeID
+	c := NewMonotonicChecker(&fixedGen{1, 2, 2, 1, 3}, func(prev, next FlakeID) {
+		violations = append(violations, [2]FlakeID{prev, next})
+	})
+	for i := 0; i < 5; i++ {
+		c.NextID()
+	}
+	if len(violations) != 2 || violations[0] != [2]FlakeID{2, 2} || violations[1] != [2]FlakeID{2, 1} {
+		t.Errorf("Test MonotonicChecker failed, got violations %v", violations)
+	}
+
+	c = NewMonotonicChecker(&fixedGen{2, 1}, nil)
+	c.NextID()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Test MonotonicChecker failed, expected panic")
+		}
+	}()
+	c.NextID()
+}
@@ -0,0 +1,73 @@
+package flake
+
+import "time"
+
+// Option configures a Generator, see NewGenerator.
+type Option func(*Generator)
+
+// WithMaxWait bounds how long NextIDErr waits for the next millisecond
+// once the sequence is exhausted, it returns ErrWaitTimeout after d.
+// NextID has no error to report and keeps waiting. Zero means no bound.
+func WithMaxWait(d time.Duration) Option {
+	return func(g *Generator) {
+		g.maxWait = d
+	}
+}
+
+// WithWaitStrategy sets how the generator waits for the next
+// millisecond, the default is WaitAdaptive.
+func WithWaitStrategy(s WaitStrategy) Option {
+	return func(g *Generator) {
+		g.wait = s
+	}
+}
+
+// WithBorrowAhead issues from the next millisecond right away once the
+// sequence of the current one is exhausted, instead of waiting, as long
+// as the ids stay at most max ahead of the clock. This trades accurate
+// timestamps during bursts for no stalls, Stats reports the borrowing.
+// A clock stepping back by at most max is absorbed the same way. max is
+// rounded up to whole milliseconds, zero or less disables borrowing.
+func WithBorrowAhead(max time.Duration) Option {
+	return func(g *Generator) {
+		g.borrow = 0
+		if max > 0 {
+			g.borrow = int64((max + time.Millisecond - 1) / time.Millisecond)
+		}
+	}
+}
+
+// WithAbandonHook sets a function called with the id of every
+// reservation which expires unconfirmed, see Generator.Reserve.
+func WithAbandonHook(fn func(FlakeID)) Option {
+	return func(g *Generator) {
+		g.onAbandon = fn
+	}
+}
+
+// WithRecent keeps the last n issued ids with their issue time, see
+// Generator.Recent.
+func WithRecent(n int) Option {
+	return func(g *Generator) {
+		if n > 0 {
+			g.recent = newRing[IssuedID](n)
+		}
+	}
+}
+
+// WithClockLog keeps the last n clock anomalies, see
+// Generator.ClockAnomalies. Wall clock steps over jump and waits for the
+// next millisecond over wait are recorded, as is any backwards clock.
+// A jump or wait of zero or less turns that check off, any wall and
+// monotonic reading differ by a little.
+func WithClockLog(n int, jump, wait time.Duration) Option {
+	return func(g *Generator) {
+		if n > 0 {
+			g.clock = &clockLog{
+				events: newRing[ClockAnomaly](n),
+				jump:   jump,
+				wait:   wait,
+			}
+		}
+	}
+}
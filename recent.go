@@ -0,0 +1,17 @@
+package flake
+
+import "time"
+
+// IssuedID is an id with the time it was issued.
+type IssuedID struct {
+	ID FlakeID
+	At time.Time
+}
+
+// Recent returns the last ids kept by WithRecent, oldest first.
+func (g *Generator) Recent() []IssuedID {
+	g.Lock()
+	defer g.Unlock()
+
+	return g.recent.list()
+}
@@ -0,0 +1,29 @@
+package flake
+
+import "testing"
+
+func TestRecent(t *testing.T) {
+	g, err := NewGenerator(123, 0, WithRecent(3))
+	if err != nil {
+		t.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+
+	if r := g.Recent(); len(r) != 0 {
+		t.Errorf("Test Recent failed, got %d ids before issuing", len(r))
+	}
+
+	var ids []FlakeID
+	for i := 0; i < 5; i++ {
+		ids = append(ids, g.NextID())
+	}
+
+	r := g.Recent()
+	if len(r) != 3 {
+		t.Fatalf("Test Recent failed, got %d ids, expected 3", len(r))
+	}
+	for i, it := range r {
+		if it.ID != ids[2+i] || it.At.IsZero() {
+			t.Errorf("Test Recent failed at %d, got %+v, expected %d", i, it, ids[2+i])
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package flake
+
+import "fmt"
+
+// The top bits of the worker id may be used as region code, which keeps
+// the id layout unchanged:
+// workerBits(10) = regionBits(3) | nodeBits(7)
+const (
+	regionBits = uint64(3)
+	nodeBits   = workerIDBits - regionBits
+	maxRegion  = int64(-1) ^ (int64(-1) << regionBits)
+	maxNode    = int64(-1) ^ (int64(-1) << nodeBits)
+)
+
+// RegionWorkerID returns the worker id of node within region.
+func RegionWorkerID(region, node int64) (int64, error) {
+	if region < 0 || region > maxRegion {
+		return 0, fmt.Errorf("region must be between 0 and %d, actual got %d",
+			maxRegion, region)
+	}
+	if node < 0 || node > maxNode {
+		return 0, fmt.Errorf("node must be between 0 and %d, actual got %d",
+			maxNode, node)
+	}
+
+	return region<<nodeBits | node, nil
+}
+
+// NewRegionGenerator is like NewGenerator with the worker id of node
+// within region.
+func NewRegionGenerator(region, node, fepoch int64, opts ...Option) (*Generator, error) {
+	workerID, err := RegionWorkerID(region, node)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewGenerator(workerID, fepoch, opts...)
+}
+
+// RegionOf returns the region code of an id issued by a region
+// generator.
+func RegionOf(id FlakeID) int64 {
+	return id.WorkerID() >> nodeBits
+}
+
+// NodeOf returns the node within its region of an id issued by a region
+// generator.
+func NodeOf(id FlakeID) int64 {
+	return id.WorkerID() & maxNode
+}
@@ -0,0 +1,22 @@
+package flake
+
+import "testing"
+
+func TestRegion(t *testing.T) {
+	g, err := NewRegionGenerator(maxRegion, 42, 0)
+	if err != nil {
+		t.Fatalf("Test region generator failed. Err: %s", err)
+	}
+
+	id := g.NextID()
+	if RegionOf(id) != maxRegion || NodeOf(id) != 42 {
+		t.Errorf("Test RegionOf failed, got region %d node %d", RegionOf(id), NodeOf(id))
+	}
+
+	if _, err := NewRegionGenerator(maxRegion+1, 0, 0); err == nil {
+		t.Errorf("Test region generator failed, invalid region accepted")
+	}
+	if _, err := RegionWorkerID(0, maxNode+1); err == nil {
+		t.Errorf("Test RegionWorkerID failed, invalid node accepted")
+	}
+}
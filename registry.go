@@ -0,0 +1,153 @@
+package flake
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Decoded is an id interpreted with a registered epoch.
+type Decoded struct {
+	Name     string
+	ID       FlakeID
+	Time     time.Time
+	WorkerID int64
+	Sequence int64
+}
+
+// Registry maps names to the epochs ids were minted under, e.g. one per
+// historical configuration of a service. Only epochs are registered, the
+// bit layout is the fixed one of this package.
+type Registry struct {
+	sync.RWMutex
+	epochs map[string]int64
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{epochs: make(map[string]int64)}
+}
+
+// defaultRegistry backs the package level Register, DecodeAs and
+// DecodeAny.
+var defaultRegistry = NewRegistry()
+
+// Register adds the epoch fepoch of ids minted under name, e.g.
+// "orders-v1". Zero is the default epoch.
+func (r *Registry) Register(name string, fepoch int64) error {
+	fepoch, err := checkConfig(0, fepoch)
+	if err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.epochs[name]; ok {
+		return fmt.Errorf("epoch %q is already registered", name)
+	}
+	r.epochs[name] = fepoch
+	return nil
+}
+
+// Unregister removes name, it is a no-op for unknown names.
+func (r *Registry) Unregister(name string) {
+	r.Lock()
+	defer r.Unlock()
+
+	delete(r.epochs, name)
+}
+
+// Epoch returns the epoch registered as name.
+func (r *Registry) Epoch(name string) (int64, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	fepoch, ok := r.epochs[name]
+	if !ok {
+		return 0, fmt.Errorf("epoch %q is not registered", name)
+	}
+	return fepoch, nil
+}
+
+// DecodeAs decodes the string s of FlakeID.ToString with the epoch
+// registered as name.
+func (r *Registry) DecodeAs(name, s string) (Decoded, error) {
+	fepoch, err := r.Epoch(name)
+	if err != nil {
+		return Decoded{}, err
+	}
+
+	id, err := DecodeString[FlakeID](s)
+	if err != nil {
+		return Decoded{}, err
+	}
+	return decodeWith(name, fepoch, id), nil
+}
+
+// maxFuture is how far after now an id may decode and still be taken as
+// plausible by DecodeAny, for clocks slightly ahead.
+const maxFuture = time.Minute
+
+// DecodeAny decodes s with every registered epoch under which it is not
+// issued in the future, the latest time first. This is a best guess, an
+// id may be plausible under several epochs.
+func (r *Registry) DecodeAny(s string) ([]Decoded, error) {
+	id, err := DecodeString[FlakeID](s)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := timeNow().Add(maxFuture)
+	var ds []Decoded
+
+	r.RLock()
+	for name, fepoch := range r.epochs {
+		if d := decodeWith(name, fepoch, id); !d.Time.After(limit) {
+			ds = append(ds, d)
+		}
+	}
+	r.RUnlock()
+
+	if len(ds) == 0 {
+		return nil, fmt.Errorf("id %d is in the future under every registered epoch", id)
+	}
+	sort.Slice(ds, func(i, j int) bool {
+		if !ds[i].Time.Equal(ds[j].Time) {
+			return ds[i].Time.After(ds[j].Time)
+		}
+		return ds[i].Name < ds[j].Name
+	})
+	return ds, nil
+}
+
+// Register adds an epoch to the default registry, see Registry.Register.
+func Register(name string, fepoch int64) error {
+	return defaultRegistry.Register(name, fepoch)
+}
+
+// Unregister removes name from the default registry.
+func Unregister(name string) {
+	defaultRegistry.Unregister(name)
+}
+
+// DecodeAs decodes with the default registry, see Registry.DecodeAs.
+func DecodeAs(name, s string) (Decoded, error) {
+	return defaultRegistry.DecodeAs(name, s)
+}
+
+// DecodeAny decodes with the default registry, see Registry.DecodeAny.
+func DecodeAny(s string) ([]Decoded, error) {
+	return defaultRegistry.DecodeAny(s)
+}
+
+func decodeWith(name string, fepoch int64, id FlakeID) Decoded {
+	return Decoded{
+		Name:     name,
+		ID:       id,
+		Time:     id.Time(fepoch).UTC(),
+		WorkerID: id.WorkerID(),
+		Sequence: id.Sequence(),
+	}
+}
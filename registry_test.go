@@ -0,0 +1,79 @@
+package flake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	reg := NewRegistry()
+	now := time.Now()
+	recent := now.Add(-24 * time.Hour).UnixMilli()
+
+	if err := reg.Register("registry-default", 0); err != nil {
+		t.Fatalf("Test Register failed. Err: %s", err)
+	}
+	if err := reg.Register("registry-recent", recent); err != nil {
+		t.Fatalf("Test Register failed. Err: %s", err)
+	}
+	if err := reg.Register("registry-recent", recent); err == nil {
+		t.Errorf("Test Register failed, duplicate name accepted")
+	}
+
+	reg.Unregister("registry-recent")
+	if _, err := reg.Epoch("registry-recent"); err == nil {
+		t.Errorf("Test Unregister failed, name still registered")
+	}
+	reg.Register("registry-recent", recent)
+
+	g, _ := NewGenerator(5, recent)
+	id := g.NextID()
+	s := id.ToString()
+
+	d, err := reg.DecodeAs("registry-recent", s)
+	if err != nil || d.ID != id || d.WorkerID != 5 || d.Time.Sub(now) > time.Second {
+		t.Errorf("Test DecodeAs failed, got %+v, err: %v", d, err)
+	}
+	if _, err := reg.DecodeAs("registry-unknown", s); err == nil {
+		t.Errorf("Test DecodeAs failed, unknown name accepted")
+	}
+
+	// a day's worth of timestamp is plausible under both epochs, the
+	// more recent reading comes first
+	ds, err := reg.DecodeAny(s)
+	if err != nil || len(ds) < 2 || ds[0].Name != "registry-recent" {
+		t.Errorf("Test DecodeAny failed, got %+v, err: %v", ds, err)
+	}
+
+	// an hour ago under the default epoch is years ahead under the
+	// recent one
+	old, _ := Compose(now.UnixMilli()-DefaultEpoch-time.Hour.Milliseconds(), 1, 0)
+	ds, err = reg.DecodeAny(old.ToString())
+	if err != nil {
+		t.Fatalf("Test DecodeAny failed. Err: %s", err)
+	}
+	for _, d := range ds {
+		if d.Name == "registry-recent" {
+			t.Errorf("Test DecodeAny failed, future reading %+v returned", d)
+		}
+	}
+
+	if _, err := reg.DecodeAny(FlakeID(1 << 63).ToString()); err == nil {
+		t.Errorf("Test DecodeAny failed, id in the future everywhere accepted")
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	if err := Register("registry-test", 0); err != nil {
+		t.Fatalf("Test Register failed. Err: %s", err)
+	}
+	defer Unregister("registry-test")
+
+	id := FlakeID(1 << 40)
+	if d, err := DecodeAs("registry-test", id.ToString()); err != nil || !d.Time.Equal(id.Time(0)) {
+		t.Errorf("Test DecodeAs failed, got %+v, err: %v", d, err)
+	}
+	if ds, err := DecodeAny(id.ToString()); err != nil || len(ds) != 1 {
+		t.Errorf("Test DecodeAny failed, got %+v, err: %v", ds, err)
+	}
+}
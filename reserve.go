@@ -0,0 +1,77 @@
+package flake
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrReservationExpired is returned by Confirm after the ttl passed.
+var ErrReservationExpired = errors.New("reservation expired")
+
+const (
+	reservationPending int32 = iota
+	reservationConfirmed
+	reservationAbandoned
+)
+
+// Reservation is an id issued ahead of the record which owns it, it is
+// abandoned unless confirmed within its ttl.
+type Reservation struct {
+	ID    FlakeID
+	g     *Generator
+	state int32
+	timer *time.Timer
+}
+
+// Reserve issues an id like NextIDErr which must be confirmed within
+// ttl, otherwise it is counted as abandoned in Stats and passed to the
+// WithAbandonHook hook.
+func (g *Generator) Reserve(ttl time.Duration) (*Reservation, error) {
+	id, err := g.NextIDErr()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Reservation{ID: id, g: g}
+
+	g.Lock()
+	g.stats.Reserved++
+	g.Unlock()
+
+	r.timer = time.AfterFunc(ttl, r.abandon)
+
+	return r, nil
+}
+
+// Confirm marks the reservation as used, confirming twice is a no-op.
+func (r *Reservation) Confirm() error {
+	if atomic.CompareAndSwapInt32(&r.state, reservationPending, reservationConfirmed) {
+		r.timer.Stop()
+
+		r.g.Lock()
+		r.g.stats.Confirmed++
+		r.g.Unlock()
+
+		return nil
+	}
+	if atomic.LoadInt32(&r.state) == reservationAbandoned {
+		return ErrReservationExpired
+	}
+	return nil
+}
+
+func (r *Reservation) abandon() {
+	if !atomic.CompareAndSwapInt32(&r.state, reservationPending, reservationAbandoned) {
+		return
+	}
+
+	r.g.Lock()
+	r.g.stats.Abandoned++
+	hook := r.g.onAbandon
+	r.g.Unlock()
+
+	if hook != nil {
+		hook(r.ID)
+	}
+}
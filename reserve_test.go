@@ -0,0 +1,46 @@
+package flake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReserve(t *testing.T) {
+	abandoned := make(chan FlakeID, 1)
+	g, err := NewGenerator(123, 0, WithAbandonHook(func(id FlakeID) {
+		abandoned <- id
+	}))
+	if err != nil {
+		t.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+
+	r, err := g.Reserve(time.Minute)
+	if err != nil {
+		t.Fatalf("Test Reserve failed. Err: %s", err)
+	}
+	if err := r.Confirm(); err != nil {
+		t.Errorf("Test Confirm failed. Err: %s", err)
+	}
+
+	r, err = g.Reserve(time.Millisecond)
+	if err != nil {
+		t.Fatalf("Test Reserve failed. Err: %s", err)
+	}
+	select {
+	case id := <-abandoned:
+		if id != r.ID {
+			t.Errorf("Test abandon hook failed, got %d, expected %d", id, r.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Test abandon hook failed, not called")
+	}
+	if err := r.Confirm(); !errors.Is(err, ErrReservationExpired) {
+		t.Errorf("Test Confirm failed, expected ErrReservationExpired, got %v", err)
+	}
+
+	st := g.Stats()
+	if st.Reserved != 2 || st.Confirmed != 1 || st.Abandoned != 1 {
+		t.Errorf("Test Stats failed, got %+v", st)
+	}
+}
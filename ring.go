@@ -0,0 +1,32 @@
+package flake
+
+// ring keeps the last len(items) values added.
+type ring[T any] struct {
+	items []T
+	next  int
+	full  bool
+}
+
+func newRing[T any](n int) *ring[T] {
+	return &ring[T]{items: make([]T, n)}
+}
+
+func (r *ring[T]) add(v T) {
+	r.items[r.next] = v
+	r.next++
+	if r.next == len(r.items) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// list returns a copy of the values, oldest first.
+func (r *ring[T]) list() []T {
+	if r == nil {
+		return nil
+	}
+	if !r.full {
+		return append([]T(nil), r.items[:r.next]...)
+	}
+	return append(append([]T(nil), r.items[r.next:]...), r.items[:r.next]...)
+}
@@ -0,0 +1,52 @@
+package flake
+
+// SchemaVersion is the version of the Schema JSON format.
+const SchemaVersion = 1
+
+// Schema describes the id layout of a generator, its JSON form is meant
+// for implementing matching encoders in other languages:
+//
+//	{
+//	  "version": 1,
+//	  "epoch_ms": 1234567891011,
+//	  "fields": [
+//	    {"name": "timestamp", "shift": 23, "bits": 41},
+//	    {"name": "worker", "shift": 13, "bits": 10},
+//	    {"name": "sequence", "shift": 0, "bits": 13}
+//	  ],
+//	  "string_encoding": "base64url"
+//	}
+//
+// The value of a field is (id >> shift) & (1<<bits - 1), the timestamp
+// field counts milliseconds since epoch_ms. Strings are the 8 bytes of
+// the id in big-endian order, encoded with padded URL-safe base64.
+type Schema struct {
+	Version        int           `json:"version"`
+	EpochMs        int64         `json:"epoch_ms"`
+	Fields         []SchemaField `json:"fields"`
+	StringEncoding string        `json:"string_encoding"`
+}
+
+// SchemaField is one bit field of a Schema.
+type SchemaField struct {
+	Name  string `json:"name"`
+	Shift uint64 `json:"shift"`
+	Bits  uint64 `json:"bits"`
+}
+
+// Schema returns the layout and epoch of the generator.
+func (g *Generator) Schema() Schema {
+	g.Lock()
+	defer g.Unlock()
+
+	return Schema{
+		Version: SchemaVersion,
+		EpochMs: g.fepoch,
+		Fields: []SchemaField{
+			{Name: "timestamp", Shift: timestampLeftShift, Bits: timestampBits},
+			{Name: "worker", Shift: workerIDShift, Bits: workerIDBits},
+			{Name: "sequence", Shift: 0, Bits: sequenceBits},
+		},
+		StringEncoding: "base64url",
+	}
+}
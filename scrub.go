@@ -0,0 +1,57 @@
+package flake
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+const workerMask = FlakeID(maxWorkerID) << workerIDShift
+
+// Scrub zeroes the worker field of id, keeping timestamp and sequence.
+// Ids of different workers with the same timestamp and sequence then
+// collide, use a Scrubber when the export must stay unique.
+func Scrub(id FlakeID) FlakeID {
+	return id &^ workerMask
+}
+
+// Scrubber replaces the worker field by a keyed permutation of it, ids
+// stay unique and time ordered while the worker ids are hidden. The
+// same key always maps a worker to the same value.
+type Scrubber struct {
+	perm [maxWorkerID + 1]uint16
+}
+
+// NewScrubber returns a Scrubber whose permutation is derived from key.
+func NewScrubber(key []byte) *Scrubber {
+	s := &Scrubber{}
+	for i := range s.perm {
+		s.perm[i] = uint16(i)
+	}
+
+	// Fisher-Yates shuffle driven by HMAC-SHA256(key, counter)
+	var ctr [8]byte
+	var block []byte
+	rand := func() uint32 {
+		if len(block) < 4 {
+			h := hmac.New(sha256.New, key)
+			h.Write(ctr[:])
+			block = h.Sum(nil)
+			binary.BigEndian.PutUint64(ctr[:], binary.BigEndian.Uint64(ctr[:])+1)
+		}
+		v := binary.BigEndian.Uint32(block)
+		block = block[4:]
+		return v
+	}
+	for i := len(s.perm) - 1; i > 0; i-- {
+		j := int(rand() % uint32(i+1))
+		s.perm[i], s.perm[j] = s.perm[j], s.perm[i]
+	}
+
+	return s
+}
+
+// Scrub returns id with its worker field permuted.
+func (s *Scrubber) Scrub(id FlakeID) FlakeID {
+	return id&^workerMask | FlakeID(s.perm[id.WorkerID()])<<workerIDShift
+}
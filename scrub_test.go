@@ -0,0 +1,29 @@
+package flake
+
+import "testing"
+
+func TestScrub(t *testing.T) {
+	id, _ := Compose(1000, 123, 7)
+	if got := Scrub(id); got.WorkerID() != 0 || got.Timestamp() != 1000 || got.Sequence() != 7 {
+		t.Errorf("Test Scrub failed, got %d|%d|%d", got.Timestamp(), got.WorkerID(), got.Sequence())
+	}
+
+	s := NewScrubber([]byte("secret"))
+	seen := make(map[int64]bool)
+	for w := int64(0); w <= maxWorkerID; w++ {
+		id, _ := Compose(1000, w, 7)
+		got := s.Scrub(id)
+		if got.Timestamp() != 1000 || got.Sequence() != 7 || seen[got.WorkerID()] {
+			t.Fatalf("Test Scrubber failed for worker %d, got %d|%d|%d",
+				w, got.Timestamp(), got.WorkerID(), got.Sequence())
+		}
+		seen[got.WorkerID()] = true
+	}
+
+	if NewScrubber([]byte("secret")).Scrub(id) != s.Scrub(id) {
+		t.Errorf("Test Scrubber failed, not deterministic")
+	}
+	if NewScrubber([]byte("other")).perm == s.perm {
+		t.Errorf("Test Scrubber failed, same permutation for different keys")
+	}
+}
@@ -0,0 +1,89 @@
+package simulate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"time"
+)
+
+// Requirements are the inputs of PlanLayout.
+type Requirements struct {
+	PeakPerSecond float64       // peak ids per second of one node
+	Nodes         int           // nodes issuing at the same time
+	Lifetime      time.Duration // how long from Epoch ids must be issued
+	Epoch         time.Time
+	Signed        bool // keep the top bit clear, for signed 64 bit storage
+}
+
+// Plan is a layout satisfying some Requirements.
+type Plan struct {
+	Layout Layout
+	Notes  string
+}
+
+// PlanLayout returns the layouts meeting req: the tightest split with
+// spare bits in the timestamp, then splits moving a spare bit each to
+// the sequence and to the workers for headroom. Note that
+// flake.Generator only issues the 41|10|13 split.
+func PlanLayout(req Requirements) ([]Plan, error) {
+	if req.PeakPerSecond <= 0 || req.Nodes <= 0 || req.Lifetime <= 0 {
+		return nil, errors.New("peak rate, nodes and lifetime must be positive")
+	}
+
+	total := uint64(64)
+	if req.Signed {
+		total = 63
+	}
+
+	perMs := uint64(math.Ceil(req.PeakPerSecond / 1000))
+	sb := bitsFor(perMs)
+	wb := bitsFor(uint64(req.Nodes))
+	lifeMs := uint64(req.Lifetime.Milliseconds())
+	tb := bitsFor(lifeMs)
+
+	if sb+wb+tb > total {
+		return nil, fmt.Errorf("needs %d sequence, %d worker and %d timestamp bits, "+
+			"only %d available", sb, wb, tb, total)
+	}
+	spare := total - sb - wb - tb
+
+	layout := func(sb, wb uint64) Layout {
+		return Layout{
+			TimestampBits: total - sb - wb,
+			WorkerIDBits:  wb,
+			SequenceBits:  sb,
+			Epoch:         req.Epoch,
+		}
+	}
+	note := func(l Layout, what string) string {
+		return fmt.Sprintf("%s: %d ids/ms per node (%.1fx peak), %d nodes (%.1fx), exhausts %s",
+			what, l.PerMillisecond(), float64(l.PerMillisecond())/float64(perMs),
+			uint64(1)<<l.WorkerIDBits, float64(uint64(1)<<l.WorkerIDBits)/float64(req.Nodes),
+			l.Exhausts().Format("2006-01-02"))
+	}
+
+	plans := []Plan{{Layout: layout(sb, wb)}}
+	plans[0].Notes = note(plans[0].Layout, "tightest, spare bits extend the lifetime")
+	if spare > 0 {
+		l := layout(sb+1, wb)
+		plans = append(plans, Plan{Layout: l, Notes: note(l, "sequence headroom")})
+		l = layout(sb, wb+1)
+		plans = append(plans, Plan{Layout: l, Notes: note(l, "worker headroom")})
+	}
+	if spare > 1 {
+		l := layout(sb+1, wb+1)
+		plans = append(plans, Plan{Layout: l, Notes: note(l, "both headroom")})
+	}
+
+	return plans, nil
+}
+
+// bitsFor returns the bits needed to count n distinct values.
+func bitsFor(n uint64) uint64 {
+	if n <= 1 {
+		return 0
+	}
+	return uint64(bits.Len64(n - 1))
+}
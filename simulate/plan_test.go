@@ -0,0 +1,65 @@
+package simulate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlanLayout(t *testing.T) {
+	epoch := time.UnixMilli(1234567891011).UTC()
+	plans, err := PlanLayout(Requirements{
+		PeakPerSecond: 5e6,
+		Nodes:         1000,
+		Lifetime:      30 * 365 * 24 * time.Hour,
+		Epoch:         epoch,
+	})
+	if err != nil {
+		t.Fatalf("Test PlanLayout failed. Err: %s", err)
+	}
+	if len(plans) != 3 {
+		t.Fatalf("Test PlanLayout failed, got %d plans", len(plans))
+	}
+
+	l := plans[0].Layout
+	if l.SequenceBits != 13 || l.WorkerIDBits != 10 || l.TimestampBits != 41 {
+		t.Errorf("Test PlanLayout failed, tightest %+v", l)
+	}
+	for _, p := range plans {
+		l := p.Layout
+		if l.TimestampBits+l.WorkerIDBits+l.SequenceBits != 64 ||
+			l.Exhausts().Before(epoch.Add(30*365*24*time.Hour)) {
+			t.Errorf("Test PlanLayout failed, invalid %+v", l)
+		}
+		t.Logf("Plan: %s", p.Notes)
+	}
+
+	// a small fleet leaves many spare timestamp bits, which must push
+	// the exhaustion later, not wrap it around
+	plans, err = PlanLayout(Requirements{
+		PeakPerSecond: 1000,
+		Nodes:         4,
+		Lifetime:      10 * 365 * 24 * time.Hour,
+		Epoch:         epoch,
+	})
+	if err != nil {
+		t.Fatalf("Test PlanLayout failed. Err: %s", err)
+	}
+	l = plans[0].Layout
+	if l.TimestampBits != 62 || !l.Exhausts().After(plans[1].Layout.Exhausts()) {
+		t.Errorf("Test PlanLayout failed, tightest %+v exhausts %s", l, l.Exhausts())
+	}
+	for _, p := range plans {
+		if p.Layout.Exhausts().Before(epoch.Add(10 * 365 * 24 * time.Hour)) {
+			t.Errorf("Test PlanLayout failed, %s", p.Notes)
+		}
+		t.Logf("Plan: %s", p.Notes)
+	}
+
+	if _, err := PlanLayout(Requirements{
+		PeakPerSecond: 1e9,
+		Nodes:         1 << 20,
+		Lifetime:      100 * 365 * 24 * time.Hour,
+	}); err == nil {
+		t.Errorf("Test PlanLayout failed, impossible requirements accepted")
+	}
+}
@@ -0,0 +1,32 @@
+package simulate
+
+import (
+	"math"
+	"time"
+)
+
+// EstimateCollisionRisk returns the probability of at least one duplicate
+// id within horizon, when nodes pick their worker ids at random, as the
+// random and IP-derived modes effectively do, and each issues perSecond
+// ids on Poisson arrivals.
+//
+// Two nodes only collide when they share a worker id and both issue in
+// the same millisecond, since both then start at sequence 0. The clocks
+// are assumed to be only skewed, not stepping back: a constant skew moves
+// which milliseconds overlap, but not how many.
+func EstimateCollisionRisk(l Layout, nodes int, perSecond float64, horizon time.Duration) float64 {
+	if nodes < 2 || perSecond <= 0 || horizon <= 0 {
+		return 0
+	}
+
+	workers := math.Exp2(float64(l.WorkerIDBits))
+	pairs := float64(nodes) * float64(nodes-1) / 2
+
+	// q is the chance a node issues in a given millisecond
+	q := -math.Expm1(-perSecond / 1000)
+	ms := float64(horizon / time.Millisecond)
+	pair := -math.Expm1(ms * math.Log1p(-q*q))
+
+	// sharing pairs are Poisson with mean pairs/workers
+	return -math.Expm1(-pairs / workers * pair)
+}
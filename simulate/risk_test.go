@@ -0,0 +1,31 @@
+package simulate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEstimateCollisionRisk(t *testing.T) {
+	l := Layout{TimestampBits: 41, WorkerIDBits: 10, SequenceBits: 13}
+
+	if p := EstimateCollisionRisk(l, 1, 1e6, time.Hour); p != 0 {
+		t.Errorf("Test EstimateCollisionRisk failed, single node has risk %g", p)
+	}
+
+	// a busy fleet sharing worker ids collides almost surely
+	if p := EstimateCollisionRisk(l, 100, 1000, time.Hour); p < 0.99 {
+		t.Errorf("Test EstimateCollisionRisk failed, busy fleet risk %g, expected near 1", p)
+	}
+
+	// two quiet nodes rarely overlap: one id a minute each for a day
+	p := EstimateCollisionRisk(l, 2, 1.0/60, 24*time.Hour)
+	if p <= 0 || p > 1e-4 {
+		t.Errorf("Test EstimateCollisionRisk failed, quiet pair risk %g, expected tiny", p)
+	}
+
+	more := EstimateCollisionRisk(l, 20, 1.0/60, 24*time.Hour)
+	wider := EstimateCollisionRisk(Layout{WorkerIDBits: 16}, 20, 1.0/60, 24*time.Hour)
+	if more <= p || wider >= more {
+		t.Errorf("Test EstimateCollisionRisk failed, risk not monotonic: %g, %g, %g", p, more, wider)
+	}
+}
@@ -0,0 +1,216 @@
+// Package simulate models id issuance under a synthetic load, to see
+// where a layout saturates before deploying it.
+//
+// A generator issues at most 2^SequenceBits ids per millisecond, the
+// rest of the requests of that millisecond wait for the next ones. The
+// model is one worker, run it with the per-worker arrival rate.
+package simulate
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	flake "github.com/liuchong/go-flake"
+)
+
+// Layout is the bit split of an id.
+type Layout struct {
+	TimestampBits uint64
+	WorkerIDBits  uint64
+	SequenceBits  uint64
+	Epoch         time.Time
+}
+
+// LayoutOf returns the layout described by a generator schema.
+func LayoutOf(s flake.Schema) Layout {
+	l := Layout{Epoch: time.UnixMilli(s.EpochMs).UTC()}
+	for _, f := range s.Fields {
+		switch f.Name {
+		case "timestamp":
+			l.TimestampBits = f.Bits
+		case "worker":
+			l.WorkerIDBits = f.Bits
+		case "sequence":
+			l.SequenceBits = f.Bits
+		}
+	}
+	return l
+}
+
+// Exhausts returns when the timestamp bits run out, clamped to the
+// latest time of int64 milliseconds. It counts in milliseconds, as a
+// time.Duration overflows past 43 bits.
+func (l Layout) Exhausts() time.Time {
+	epoch := l.Epoch.UnixMilli()
+	if l.TimestampBits >= 63 {
+		return time.UnixMilli(math.MaxInt64).UTC()
+	}
+	span := int64(1)<<l.TimestampBits - 1
+	if epoch > math.MaxInt64-span {
+		return time.UnixMilli(math.MaxInt64).UTC()
+	}
+	return time.UnixMilli(epoch + span).UTC()
+}
+
+// PerMillisecond is the most ids issued per millisecond and worker.
+func (l Layout) PerMillisecond() uint64 {
+	return 1 << l.SequenceBits
+}
+
+// Arrivals returns the number of requests arriving in millisecond ms of
+// the simulation.
+type Arrivals func(ms int64) uint64
+
+// Constant arrives at perSecond requests per second, spread evenly.
+func Constant(perSecond float64) Arrivals {
+	return func(ms int64) uint64 {
+		return uint64(math.Floor(float64(ms+1)*perSecond/1000) -
+			math.Floor(float64(ms)*perSecond/1000))
+	}
+}
+
+// Poisson arrives at perSecond requests per second on average, with
+// Poisson distributed counts per millisecond.
+func Poisson(perSecond float64, seed int64) Arrivals {
+	r := rand.New(rand.NewSource(seed))
+	lambda := perSecond / 1000
+	return func(ms int64) uint64 {
+		// normal approximation for large rates, Knuth below
+		if lambda > 30 {
+			v := math.Round(lambda + math.Sqrt(lambda)*r.NormFloat64())
+			if v < 0 {
+				return 0
+			}
+			return uint64(v)
+		}
+		l, k, p := math.Exp(-lambda), uint64(0), 1.0
+		for {
+			p *= r.Float64()
+			if p <= l {
+				return k
+			}
+			k++
+		}
+	}
+}
+
+// Burst arrives at base, and at peak for the first width of every
+// period.
+func Burst(base, peak Arrivals, period, width time.Duration) Arrivals {
+	p, w := period.Milliseconds(), width.Milliseconds()
+	return func(ms int64) uint64 {
+		if p > 0 && ms%p < w {
+			return peak(ms)
+		}
+		return base(ms)
+	}
+}
+
+// Report is the result of Run.
+type Report struct {
+	Layout         Layout
+	Duration       time.Duration
+	Requests       uint64
+	Issued         uint64
+	SaturatedMs    uint64        // milliseconds issuing the full sequence
+	MaxBacklog     uint64        // most requests waiting at a millisecond end
+	MeanWait       time.Duration // mean wait per request
+	P99Wait        time.Duration
+	MaxWait        time.Duration
+	SaturationRate float64 // requests per second at which waits start
+	Exhausts       time.Time
+}
+
+func (r Report) String() string {
+	return fmt.Sprintf("sequence bits %d: %d/%d issued in %s, saturated %d ms, "+
+		"max backlog %d, wait mean %s p99 %s max %s, saturates at %.0f/s, exhausts %s",
+		r.Layout.SequenceBits, r.Issued, r.Requests, r.Duration, r.SaturatedMs,
+		r.MaxBacklog, r.MeanWait, r.P99Wait, r.MaxWait, r.SaturationRate,
+		r.Exhausts.Format(time.RFC3339))
+}
+
+type batch struct {
+	ms int64 // arrival
+	n  uint64
+}
+
+// Run simulates d of arrivals for one worker with l, in millisecond
+// steps. Requests still waiting at the end are issued after d.
+func Run(l Layout, arrivals Arrivals, d time.Duration) Report {
+	capacity := l.PerMillisecond()
+	rep := Report{
+		Layout:         l,
+		Duration:       d,
+		SaturationRate: float64(capacity) * 1000,
+		Exhausts:       l.Exhausts(),
+	}
+
+	var queue []batch
+	var backlog uint64
+	waits := make(map[int64]uint64) // wait in ms -> requests
+	serve := func(ms int64) {
+		left := capacity
+		for left > 0 && len(queue) > 0 {
+			b := &queue[0]
+			n := b.n
+			if n > left {
+				n = left
+			}
+			waits[ms-b.ms] += n
+			b.n -= n
+			left -= n
+			backlog -= n
+			rep.Issued += n
+			if b.n == 0 {
+				queue = queue[1:]
+			}
+		}
+		if left == 0 {
+			rep.SaturatedMs++
+		}
+	}
+
+	total := d.Milliseconds()
+	for ms := int64(0); ms < total; ms++ {
+		if n := arrivals(ms); n > 0 {
+			queue = append(queue, batch{ms, n})
+			backlog += n
+			rep.Requests += n
+		}
+		serve(ms)
+		if backlog > rep.MaxBacklog {
+			rep.MaxBacklog = backlog
+		}
+	}
+	for ms := total; backlog > 0; ms++ {
+		serve(ms)
+	}
+
+	keys := make([]int64, 0, len(waits))
+	var sum float64
+	for w, n := range waits {
+		keys = append(keys, w)
+		sum += float64(w) * float64(n)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	if rep.Issued > 0 {
+		rep.MeanWait = time.Duration(sum / float64(rep.Issued) * float64(time.Millisecond))
+		rep.MaxWait = time.Duration(keys[len(keys)-1]) * time.Millisecond
+
+		var seen uint64
+		p99 := uint64(math.Ceil(float64(rep.Issued) * 0.99))
+		for _, w := range keys {
+			seen += waits[w]
+			if seen >= p99 {
+				rep.P99Wait = time.Duration(w) * time.Millisecond
+				break
+			}
+		}
+	}
+
+	return rep
+}
@@ -0,0 +1,57 @@
+package simulate
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	flake "github.com/liuchong/go-flake"
+)
+
+func TestRun(t *testing.T) {
+	g, _ := flake.NewGenerator(0, 0)
+	l := LayoutOf(g.Schema())
+	if l.SequenceBits != 13 || l.TimestampBits != 41 || l.Exhausts().Year() != 2078 {
+		t.Fatalf("Test LayoutOf failed, got %+v", l)
+	}
+
+	// 5M/s is below the 8192/ms of 13 bits but over the 4096/ms of 12
+	load := Constant(5e6)
+	rep := Run(l, load, time.Second)
+	if rep.Requests != 5e6 || rep.Issued != rep.Requests || rep.MaxWait != 0 {
+		t.Errorf("Test Run failed, 13 bits %s", rep)
+	}
+
+	l.SequenceBits = 12
+	rep = Run(l, load, time.Second)
+	if rep.Issued != rep.Requests || rep.MaxWait == 0 || rep.SaturatedMs == 0 {
+		t.Errorf("Test Run failed, 12 bits %s", rep)
+	}
+
+	bursty := Burst(Poisson(1e6, 1), Constant(2e7), time.Second, 10*time.Millisecond)
+	rep = Run(LayoutOf(g.Schema()), bursty, 3*time.Second)
+	if rep.MaxBacklog == 0 || rep.P99Wait > rep.MaxWait {
+		t.Errorf("Test Run failed, burst %s", rep)
+	}
+	t.Logf("Burst: %s", rep)
+}
+
+func TestExhausts(t *testing.T) {
+	epoch := time.UnixMilli(1234567891011).UTC()
+
+	prev := epoch
+	for bits := uint64(41); bits <= 53; bits++ {
+		l := Layout{TimestampBits: bits, Epoch: epoch}
+		got := l.Exhausts()
+		want := epoch.UnixMilli() + int64(1)<<bits - 1
+		if got.UnixMilli() != want || !got.After(prev) {
+			t.Errorf("Test Exhausts failed for %d bits, got %s", bits, got)
+		}
+		prev = got
+	}
+
+	l := Layout{TimestampBits: 63, Epoch: epoch}
+	if l.Exhausts().UnixMilli() != math.MaxInt64 {
+		t.Errorf("Test Exhausts failed, 63 bits not clamped, got %s", l.Exhausts())
+	}
+}
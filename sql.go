@@ -0,0 +1,73 @@
+package flake
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+)
+
+// Value implements driver.Valuer, the id is stored as BIGINT. Ids are
+// unsigned, past 2^63 they are stored as negative numbers and scanned
+// back unchanged.
+func (id FlakeID) Value() (driver.Value, error) {
+	return int64(id), nil
+}
+
+// Scan implements sql.Scanner for BIGINT columns, drivers returning the
+// number as text are supported as well.
+func (id *FlakeID) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case int64:
+		*id = FlakeID(v)
+	case uint64:
+		*id = FlakeID(v)
+	case []byte:
+		return id.scanText(string(v))
+	case string:
+		return id.scanText(v)
+	case nil:
+		return fmt.Errorf("cannot scan NULL into FlakeID, use NullFlakeID")
+	default:
+		return fmt.Errorf("cannot scan %T into FlakeID", src)
+	}
+	return nil
+}
+
+func (id *FlakeID) scanText(s string) error {
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*id = FlakeID(v)
+		return nil
+	}
+
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("cannot scan %q into FlakeID: %w", s, err)
+	}
+	*id = FlakeID(v)
+	return nil
+}
+
+// NullFlakeID is a FlakeID which may be NULL, like sql.NullInt64.
+type NullFlakeID struct {
+	FlakeID FlakeID
+	Valid   bool // Valid is true if FlakeID is not NULL
+}
+
+// Value implements driver.Valuer.
+func (n NullFlakeID) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.FlakeID.Value()
+}
+
+// Scan implements sql.Scanner.
+func (n *NullFlakeID) Scan(src interface{}) error {
+	if src == nil {
+		n.FlakeID, n.Valid = 0, false
+		return nil
+	}
+
+	n.Valid = true
+	return n.FlakeID.Scan(src)
+}
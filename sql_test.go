@@ -0,0 +1,39 @@
+package flake
+
+import "testing"
+
+func TestSQL(t *testing.T) {
+	for _, id := range []FlakeID{0, 1234567890123, 1 << 63, ^FlakeID(0)} {
+		v, err := id.Value()
+		if err != nil {
+			t.Fatalf("Test Value failed. Err: %s", err)
+		}
+
+		var got FlakeID
+		if err := got.Scan(v); err != nil || got != id {
+			t.Errorf("Test Scan failed, got %d, expected %d, err: %v", got, id, err)
+		}
+	}
+
+	var id FlakeID
+	if err := id.Scan([]byte("-1")); err != nil || id != ^FlakeID(0) {
+		t.Errorf("Test Scan failed, got %d, err: %v", id, err)
+	}
+	if err := id.Scan("18446744073709551615"); err != nil || id != ^FlakeID(0) {
+		t.Errorf("Test Scan failed, got %d, err: %v", id, err)
+	}
+	if err := id.Scan(nil); err == nil {
+		t.Errorf("Test Scan failed, NULL accepted")
+	}
+
+	var n NullFlakeID
+	if err := n.Scan(nil); err != nil || n.Valid {
+		t.Errorf("Test NullFlakeID.Scan failed, got %+v, err: %v", n, err)
+	}
+	if v, _ := n.Value(); v != nil {
+		t.Errorf("Test NullFlakeID.Value failed, got %v", v)
+	}
+	if err := n.Scan(int64(42)); err != nil || !n.Valid || n.FlakeID != 42 {
+		t.Errorf("Test NullFlakeID.Scan failed, got %+v, err: %v", n, err)
+	}
+}
@@ -0,0 +1,54 @@
+package flake
+
+import "time"
+
+// Stats are the counters of a Generator since it was created, take the
+// difference of two snapshots to get them per interval.
+type Stats struct {
+	Issued      uint64        // ids issued
+	Exhaustions uint64        // times the sequence ran out within a millisecond
+	Waited      time.Duration // total time spent waiting for the next millisecond
+	MaxWaited   time.Duration // longest single wait
+	Borrowed    uint64        // milliseconds borrowed ahead, see WithBorrowAhead
+	MaxDebt     time.Duration // furthest an id was issued ahead of the clock
+	Reserved    uint64        // ids handed out by Reserve
+	Confirmed   uint64        // reservations confirmed in time
+	Abandoned   uint64        // reservations expired unconfirmed
+}
+
+// Sub returns the counters accumulated from prev to s, MaxWaited and
+// MaxDebt are kept from s.
+func (s Stats) Sub(prev Stats) Stats {
+	return Stats{
+		Issued:      s.Issued - prev.Issued,
+		Exhaustions: s.Exhaustions - prev.Exhaustions,
+		Waited:      s.Waited - prev.Waited,
+		MaxWaited:   s.MaxWaited,
+		Borrowed:    s.Borrowed - prev.Borrowed,
+		MaxDebt:     s.MaxDebt,
+		Reserved:    s.Reserved - prev.Reserved,
+		Confirmed:   s.Confirmed - prev.Confirmed,
+		Abandoned:   s.Abandoned - prev.Abandoned,
+	}
+}
+
+// Stats returns a snapshot of the generator counters.
+func (g *Generator) Stats() Stats {
+	g.Lock()
+	defer g.Unlock()
+
+	return g.stats
+}
+
+// recordWait counts one exhaustion at lastTs which waited d. g must be
+// locked.
+func (g *Generator) recordWait(lastTs int64, d time.Duration) {
+	g.stats.Exhaustions++
+	g.stats.Waited += d
+	if d > g.stats.MaxWaited {
+		g.stats.MaxWaited = d
+	}
+	if g.clock != nil {
+		g.clock.checkWait(lastTs, d)
+	}
+}
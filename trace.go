@@ -0,0 +1,68 @@
+package flake
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// TraceID is a 128-bit W3C/OpenTelemetry trace id whose high 64 bits
+// are a FlakeID and low 64 bits are random. Trace ids then sort and
+// range-query by time like flake ids, and the random half satisfies the
+// random trace-id flag of W3C Trace Context level 2.
+type TraceID [16]byte
+
+// NextTraceID returns a new trace id, errors are those of NextIDErr
+// and of reading crypto/rand.
+func (g *Generator) NextTraceID() (TraceID, error) {
+	var t TraceID
+
+	id, err := g.NextIDErr()
+	if err != nil {
+		return t, err
+	}
+	PutBytes(t[:8], id)
+
+	if _, err := rand.Read(t[8:]); err != nil {
+		return TraceID{}, err
+	}
+
+	return t, nil
+}
+
+// ParseTraceID parses the 32 lowercase hex digits of a trace id.
+func ParseTraceID(s string) (TraceID, error) {
+	var t TraceID
+	if len(s) != 32 {
+		return t, fmt.Errorf("trace id must be 32 hex digits, actual got %d", len(s))
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c >= 'A' && c <= 'F' {
+			return t, fmt.Errorf("trace id must be lowercase hex, actual got %q", s)
+		}
+	}
+	if _, err := hex.Decode(t[:], []byte(s)); err != nil {
+		return TraceID{}, err
+	}
+	if !t.IsValid() {
+		return TraceID{}, fmt.Errorf("trace id must not be all zeros")
+	}
+
+	return t, nil
+}
+
+// IsValid reports whether t is not all zeros, as W3C requires.
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+// FlakeID returns the flake id of the high 64 bits.
+func (t TraceID) FlakeID() FlakeID {
+	id, _ := DecodeBytes[FlakeID](t[:8])
+	return id
+}
+
+// String returns the 32 lowercase hex digits of t, as in traceparent.
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
@@ -0,0 +1,34 @@
+package flake
+
+import "testing"
+
+func TestTraceID(t *testing.T) {
+	g, err := NewGenerator(123, 0)
+	if err != nil {
+		t.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+
+	t0, err := g.NextTraceID()
+	if err != nil {
+		t.Fatalf("Test NextTraceID failed. Err: %s", err)
+	}
+	t1, _ := g.NextTraceID()
+
+	if !t0.IsValid() || t0.FlakeID().WorkerID() != 123 || t1.FlakeID() <= t0.FlakeID() {
+		t.Errorf("Test NextTraceID failed, got %s and %s", t0, t1)
+	}
+
+	s := t0.String()
+	if len(s) != 32 {
+		t.Errorf("Test TraceID.String failed, got %q", s)
+	}
+	if got, err := ParseTraceID(s); err != nil || got != t0 {
+		t.Errorf("Test ParseTraceID failed, got %s, err: %v", got, err)
+	}
+
+	for _, bad := range []string{"", "00000000000000000000000000000000", "4BF92F3577B34DA6A3CE929D0E0E4736"} {
+		if _, err := ParseTraceID(bad); err == nil {
+			t.Errorf("Test ParseTraceID failed, %q accepted", bad)
+		}
+	}
+}
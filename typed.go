@@ -0,0 +1,67 @@
+package flake
+
+import "database/sql/driver"
+
+// TypedID is a FlakeID tagged with the entity type T, TypedID[User] and
+// TypedID[Order] can't be assigned to each other. T is never used at
+// run time, an empty struct type is enough.
+type TypedID[T any] FlakeID
+
+// NewTypedID returns the next id of gen tagged with T.
+func NewTypedID[T any](gen IDGenerator) TypedID[T] {
+	return TypedID[T](gen.NextID())
+}
+
+// FlakeID returns id as untyped FlakeID.
+func (id TypedID[T]) FlakeID() FlakeID {
+	return FlakeID(id)
+}
+
+// ToBytes convert id to byte array.
+func (id TypedID[T]) ToBytes() []byte {
+	return EncodeBytes(id)
+}
+
+// ToString encode id to URL-compatible base64 string.
+func (id TypedID[T]) ToString() string {
+	return EncodeString(id)
+}
+
+// FromString decode URL-compatible base64 string to id.
+func (id *TypedID[T]) FromString(s string) error {
+	v, err := DecodeString[TypedID[T]](s)
+	if err != nil {
+		return err
+	}
+
+	*id = v
+
+	return nil
+}
+
+// MarshalJSON automatically convert id to string for JSON.
+func (id TypedID[T]) MarshalJSON() ([]byte, error) {
+	return EncodeJSON(id)
+}
+
+// UnmarshalJSON convert JSON string to id.
+func (id *TypedID[T]) UnmarshalJSON(data []byte) error {
+	v, err := DecodeJSON[TypedID[T]](data)
+	if err != nil {
+		return err
+	}
+
+	*id = v
+
+	return nil
+}
+
+// Value implements driver.Valuer like FlakeID.
+func (id TypedID[T]) Value() (driver.Value, error) {
+	return FlakeID(id).Value()
+}
+
+// Scan implements sql.Scanner like FlakeID.
+func (id *TypedID[T]) Scan(src interface{}) error {
+	return (*FlakeID)(id).Scan(src)
+}
@@ -0,0 +1,38 @@
+package flake
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type testUser struct{}
+
+func TestTypedID(t *testing.T) {
+	g, err := NewGenerator(123, 0)
+	if err != nil {
+		t.Fatalf("Test flake ID generator failed. Err: %s", err)
+	}
+
+	id := NewTypedID[testUser](g)
+	if id.FlakeID().WorkerID() != 123 {
+		t.Errorf("Test NewTypedID failed, worker %d", id.FlakeID().WorkerID())
+	}
+
+	data, err := json.Marshal(struct{ ID TypedID[testUser] }{id})
+	if err != nil {
+		t.Fatalf("Test TypedID JSON failed. Err: %s", err)
+	}
+	var got struct{ ID TypedID[testUser] }
+	if err := json.Unmarshal(data, &got); err != nil || got.ID != id {
+		t.Errorf("Test TypedID JSON failed, got %d, err: %v", got.ID, err)
+	}
+	if got.ID.ToString() != id.FlakeID().ToString() {
+		t.Errorf("Test TypedID ToString failed, %s", got.ID.ToString())
+	}
+
+	v, _ := id.Value()
+	var scanned TypedID[testUser]
+	if err := scanned.Scan(v); err != nil || scanned != id {
+		t.Errorf("Test TypedID Scan failed, got %d, err: %v", scanned, err)
+	}
+}
@@ -2,6 +2,7 @@ package util
 
 import (
 	"errors"
+	"fmt"
 	"net"
 	"strconv"
 	"strings"
@@ -126,3 +127,52 @@ func IP4toInt(IPv4Addr net.IP) int64 {
 
 	return sum
 }
+
+// GetIPInNet returns the first IPv4 address of this host within n.
+func GetIPInNet(n *net.IPNet) (net.IP, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, addr := range addrs {
+		var ip net.IP
+
+		switch v := addr.(type) {
+		case *net.IPNet:
+			ip = v.IP
+		case *net.IPAddr:
+			ip = v.IP
+		}
+
+		if ip4 := ip.To4(); ip4 != nil && n.Contains(ip4) {
+			return ip4, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no address in %s", n)
+}
+
+// SubnetWorkerID returns the host part of ip within the IPv4 subnet n as
+// worker id. It is unique within n as long as n has at most bits host
+// bits, e.g. a /22 for 10 bits, larger subnets are an error.
+func SubnetWorkerID(ip net.IP, n *net.IPNet, bits uint) (int64, error) {
+	ip4, mask := ip.To4(), n.Mask
+	if ip4 == nil || len(mask) != net.IPv4len {
+		return 0, errors.New("subnet worker ids need IPv4")
+	}
+	if !n.Contains(ip4) {
+		return 0, fmt.Errorf("%s is not in %s", ip, n)
+	}
+
+	ones, size := mask.Size()
+	if hostBits := uint(size - ones); hostBits > bits {
+		return 0, fmt.Errorf("%s has %d host bits, only %d worker bits", n, hostBits, bits)
+	}
+
+	var host int64
+	for i := range ip4 {
+		host = host<<8 | int64(ip4[i]&^mask[i])
+	}
+	return host, nil
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"net"
 	"testing"
 )
 
@@ -12,3 +13,24 @@ func TestGetIP(t *testing.T) {
 	t.Logf("Got IP: %+v\n", ip)
 	t.Logf("Got IP number: %d\n", IP4toInt(ip))
 }
+
+func TestSubnetWorkerID(t *testing.T) {
+	_, n, _ := net.ParseCIDR("10.1.4.0/22")
+
+	w, err := SubnetWorkerID(net.ParseIP("10.1.7.255"), n, 10)
+	if err != nil || w != 1023 {
+		t.Errorf("Test SubnetWorkerID failed, got %d, err: %v", w, err)
+	}
+	if w, _ := SubnetWorkerID(net.ParseIP("10.1.4.1"), n, 10); w != 1 {
+		t.Errorf("Test SubnetWorkerID failed, got %d", w)
+	}
+
+	if _, err := SubnetWorkerID(net.ParseIP("10.1.8.1"), n, 10); err == nil {
+		t.Errorf("Test SubnetWorkerID failed, address outside subnet accepted")
+	}
+
+	_, wide, _ := net.ParseCIDR("10.0.0.0/16")
+	if _, err := SubnetWorkerID(net.ParseIP("10.0.0.1"), wide, 10); err == nil {
+		t.Errorf("Test SubnetWorkerID failed, subnet wider than worker bits accepted")
+	}
+}
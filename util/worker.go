@@ -0,0 +1,79 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"os"
+	"strings"
+)
+
+// HostPIDWorkerID mixes a host number, e.g. of IP4toInt, with a process
+// id into a worker id below 1<<bits: the low bits-pidBits bits of host
+// and pidBits bits of a hash of pid. Processes of one host then get
+// different worker ids unless their pids collide in the hash.
+func HostPIDWorkerID(host int64, pid int, bits, pidBits uint) int64 {
+	if pidBits > bits {
+		pidBits = bits
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte{byte(pid >> 24), byte(pid >> 16), byte(pid >> 8), byte(pid)})
+	pidPart := int64(h.Sum32()) & (1<<pidBits - 1)
+
+	hostPart := host & (1<<(bits-pidBits) - 1)
+
+	return hostPart<<pidBits | pidPart
+}
+
+// HashWorkerID hashes name, e.g. a FQDN, into a worker id below 1<<bits.
+// A name in overrides gets its configured id instead. Hashes landing on
+// an id in exclude, e.g. ids pinned by overrides, move on to the next
+// free id.
+func HashWorkerID(name string, bits uint, overrides map[string]int64, exclude []int64) (int64, error) {
+	size := int64(1) << bits
+
+	if id, ok := overrides[name]; ok {
+		if id < 0 || id >= size {
+			return 0, fmt.Errorf("override of %s must be between 0 and %d, actual got %d",
+				name, size-1, id)
+		}
+		return id, nil
+	}
+
+	excluded := make(map[int64]bool, len(exclude)+len(overrides))
+	for _, id := range exclude {
+		excluded[id] = true
+	}
+	for _, id := range overrides {
+		excluded[id] = true
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	id := int64(h.Sum64() % uint64(size))
+	for i := int64(0); i < size; i++ {
+		if !excluded[id] {
+			return id, nil
+		}
+		id = (id + 1) % size
+	}
+
+	return 0, errors.New("all worker ids are excluded")
+}
+
+// FQDN returns the fully qualified name of this host, or the plain
+// hostname if it can't be resolved.
+func FQDN() (string, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		return "", err
+	}
+
+	cname, err := net.LookupCNAME(host)
+	if err != nil || cname == "" {
+		return host, nil
+	}
+	return strings.TrimSuffix(cname, "."), nil
+}
@@ -0,0 +1,47 @@
+package util
+
+import "testing"
+
+func TestHostPIDWorkerID(t *testing.T) {
+	seen := make(map[int64]int)
+	for pid := 1000; pid < 1016; pid++ {
+		w := HostPIDWorkerID(0x0a000105, pid, 10, 4)
+		if w < 0 || w >= 1<<10 || w>>4 != 0x05 {
+			t.Fatalf("Test HostPIDWorkerID failed, got %d for pid %d", w, pid)
+		}
+		seen[w]++
+	}
+	if len(seen) < 8 {
+		t.Errorf("Test HostPIDWorkerID failed, only %d distinct ids for 16 pids", len(seen))
+	}
+
+	if w := HostPIDWorkerID(0x0a000105, 1000, 10, 0); w != 0x105 {
+		t.Errorf("Test HostPIDWorkerID failed, got %d without pid bits", w)
+	}
+}
+
+func TestHashWorkerID(t *testing.T) {
+	w, err := HashWorkerID("db1.example.com", 10, nil, nil)
+	if err != nil || w < 0 || w >= 1<<10 {
+		t.Fatalf("Test HashWorkerID failed, got %d, err: %v", w, err)
+	}
+	if again, _ := HashWorkerID("db1.example.com", 10, nil, nil); again != w {
+		t.Errorf("Test HashWorkerID failed, not stable: %d and %d", w, again)
+	}
+
+	// a pinned id is skipped by hashed names
+	moved, err := HashWorkerID("db1.example.com", 10, map[string]int64{"db2.example.com": w}, nil)
+	if err != nil || moved == w {
+		t.Errorf("Test HashWorkerID failed, got pinned %d, err: %v", moved, err)
+	}
+	if got, _ := HashWorkerID("db2.example.com", 10, map[string]int64{"db2.example.com": w}, nil); got != w {
+		t.Errorf("Test HashWorkerID failed, override ignored, got %d", got)
+	}
+
+	if _, err := HashWorkerID("x", 1, nil, []int64{0, 1}); err == nil {
+		t.Errorf("Test HashWorkerID failed, expected error with all ids excluded")
+	}
+	if _, err := HashWorkerID("x", 10, map[string]int64{"x": 1024}, nil); err == nil {
+		t.Errorf("Test HashWorkerID failed, out of range override accepted")
+	}
+}
@@ -0,0 +1,101 @@
+package flake
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// WaitStrategy is how a Generator waits for the next millisecond once
+// the sequence is exhausted.
+type WaitStrategy int
+
+const (
+	// WaitAdaptive sleeps for most of the rest of the millisecond and
+	// spins the tail, the spin tracks how much time.Sleep oversleeps.
+	WaitAdaptive WaitStrategy = iota
+	// WaitSleep sleeps for the rest of the millisecond.
+	WaitSleep
+	// WaitSpin busy-spins on the clock, lowest latency but burns a CPU.
+	WaitSpin
+	// WaitYield calls runtime.Gosched between clock reads.
+	WaitYield
+)
+
+func (s WaitStrategy) String() string {
+	switch s {
+	case WaitAdaptive:
+		return "adaptive"
+	case WaitSleep:
+		return "sleep"
+	case WaitSpin:
+		return "spin"
+	case WaitYield:
+		return "yield"
+	}
+	return fmt.Sprintf("WaitStrategy(%d)", int(s))
+}
+
+const (
+	minSpin = 50 * time.Microsecond
+	maxSpin = time.Millisecond
+)
+
+// waitNext waits until the clock moves past lastTs, or returns
+// ErrWaitTimeout at a non-zero deadline. It runs with g unlocked, spin is
+// the estimate of WaitAdaptive and returned updated.
+func (g *Generator) waitNext(lastTs int64, deadline time.Time, spin time.Duration) (time.Duration, error) {
+	for {
+		ts, rem := getTsInfo()
+		if ts > lastTs {
+			return spin, nil
+		}
+
+		d := time.Duration(rem)
+		if !deadline.IsZero() {
+			left := time.Until(deadline)
+			if left <= 0 {
+				return spin, fmt.Errorf("%w: waited %s", ErrWaitTimeout, g.maxWait)
+			}
+			if d > left {
+				d = left
+			}
+		}
+
+		switch g.wait {
+		case WaitSleep:
+			time.Sleep(d)
+		case WaitSpin:
+		case WaitYield:
+			runtime.Gosched()
+		default:
+			spin = adaptiveWait(d, spin)
+		}
+	}
+}
+
+// adaptiveWait sleeps d less the spin estimate, or yields once if d is
+// already within it, and returns the updated estimate.
+func adaptiveWait(d, spin time.Duration) time.Duration {
+	if spin == 0 {
+		spin = minSpin
+	}
+	if d <= spin {
+		runtime.Gosched()
+		return spin
+	}
+
+	want := d - spin
+	start := time.Now()
+	time.Sleep(want)
+	over := time.Since(start) - want
+
+	// moving average of the oversleep, 1/8 weight for the latest sample
+	spin += (over - spin) / 8
+	if spin < minSpin {
+		spin = minSpin
+	} else if spin > maxSpin {
+		spin = maxSpin
+	}
+	return spin
+}
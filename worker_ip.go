@@ -0,0 +1,54 @@
+//go:build !tinygo && !flake_tiny && !flake_nonet
+
+package flake
+
+import (
+	"net"
+	"os"
+
+	"github.com/liuchong/go-flake/util"
+)
+
+// PIDWorkerID derives a worker id from the host IP and the process id,
+// with pidBits of the worker bits taken from the pid. Use it instead of
+// the IP-derived default when several processes run on one host:
+//
+//	workerID, err := flake.PIDWorkerID(4)
+//	...
+//	g, err := flake.NewGenerator(workerID, 0)
+func PIDWorkerID(pidBits uint) (int64, error) {
+	ip, err := util.GetIP()
+	if err != nil {
+		return 0, err
+	}
+
+	return util.HostPIDWorkerID(util.IP4toInt(ip), os.Getpid(),
+		uint(workerIDBits), pidBits), nil
+}
+
+// HostnameWorkerID hashes the FQDN of this host into a worker id, see
+// util.HashWorkerID for overrides and exclude.
+func HostnameWorkerID(overrides map[string]int64, exclude []int64) (int64, error) {
+	name, err := util.FQDN()
+	if err != nil {
+		return 0, err
+	}
+
+	return util.HashWorkerID(name, uint(workerIDBits), overrides, exclude)
+}
+
+// SubnetWorkerID uses the host part of this host's address within cidr,
+// e.g. "10.1.4.0/22", as worker id, which is unique within the subnet.
+func SubnetWorkerID(cidr string) (int64, error) {
+	_, n, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return 0, err
+	}
+
+	ip, err := util.GetIPInNet(n)
+	if err != nil {
+		return 0, err
+	}
+
+	return util.SubnetWorkerID(ip, n, uint(workerIDBits))
+}